@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erikbryant/wow/internal/output"
+	"github.com/erikbryant/wow/internal/path"
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+// exportChanged returns true if the wowMerchant price cache entry for this item changed
+func exportChanged(oldItem, newItem wowitem.Item) bool {
+	return oldItem.SellPriceRealizable() != newItem.SellPriceRealizable() || oldItem.Cosmetic() != newItem.Cosmetic()
+}
+
+// appendChangeLog appends the field changes, stamped with when, to the change log
+func appendChangeLog(filename string, changes []fieldChange, when time.Time) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open change log: %w", err)
+	}
+
+	for _, c := range changes {
+		_, err = fmt.Fprintf(f, "%s  %7d  %-12s %s -> %s  %s\n", when.Format(time.RFC3339), c.ID, c.Field, c.Old, c.New, c.Name)
+		if err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write change log: %w", err)
+		}
+	}
+
+	return f.Close()
+}
+
+// summarizeChanges writes how many items and fields changed, by field
+func summarizeChanges(w io.Writer, changes []fieldChange) {
+	items := map[int64]struct{}{}
+	byField := map[string]int{}
+
+	for _, c := range changes {
+		items[c.ID] = struct{}{}
+		byField[c.Field]++
+	}
+
+	fmt.Fprintf(w, "%d items changed", len(items))
+	for _, field := range diffFields {
+		if byField[field.name] > 0 {
+			fmt.Fprintf(w, ", %d %s", byField[field.name], field.name)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// updateExport regenerates the wowMerchant price cache, or marks it as needing
+// regeneration, when refreshed items changed their entries in it. It returns the
+// number of items the price cache is out of date for.
+func updateExport(paths *path.Paths, wowItems *wowitem.Persistence, changedIDs []int64, regenerate bool) (int, error) {
+	if len(changedIDs) == 0 {
+		return 0, nil
+	}
+
+	if regenerate {
+		err := os.WriteFile(paths.PriceCache, []byte(output.Lua(wowItems)), 0600)
+		if err != nil {
+			return 0, fmt.Errorf("failed to regenerate price cache: %w", err)
+		}
+		err = os.Remove(paths.PriceCacheStale)
+		if err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	// Keep the items marked by earlier refreshes; the price cache is still wrong for them too
+	staleIDs, err := readStale(paths.PriceCacheStale)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark price cache stale: %w", err)
+	}
+	for _, id := range changedIDs {
+		if !slices.Contains(staleIDs, id) {
+			staleIDs = append(staleIDs, id)
+		}
+	}
+	slices.Sort(staleIDs)
+
+	ids := []string{}
+	for _, id := range staleIDs {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+
+	err = os.WriteFile(paths.PriceCacheStale, []byte(strings.Join(ids, "\n")+"\n"), 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark price cache stale: %w", err)
+	}
+
+	return len(staleIDs), nil
+}
+
+// readStale returns the item IDs listed in the price cache stale marker, if there is one
+func readStale(filename string) ([]int64, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return []int64{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int64{}
+	for _, line := range strings.Fields(string(data)) {
+		id, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
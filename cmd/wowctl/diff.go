@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/erikbryant/wow/internal/common"
+	"github.com/erikbryant/wow/internal/path"
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+// fieldChange is one field that differs between the old and new copy of an item
+type fieldChange struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// itemDiff holds the differences between two item persistences
+type itemDiff struct {
+	Added   []int64       `json:"added"`
+	Removed []int64       `json:"removed"`
+	Changed []fieldChange `json:"changed"`
+}
+
+// diffFields are the item fields compared, in output order
+var diffFields = []struct {
+	name  string
+	value func(wowitem.Item) string
+}{
+	{"name", func(i wowitem.Item) string { return i.Name() }},
+	{"sell price", func(i wowitem.Item) string { return common.Gold(i.SellPriceAdvertised()) }},
+	{"level", func(i wowitem.Item) string { return fmt.Sprintf("%d", i.ItemLevel()) }},
+	{"class", func(i wowitem.Item) string { return i.ItemClassName() }},
+	{"appearances", func(i wowitem.Item) string { return fmt.Sprintf("%v", i.Appearances()) }},
+}
+
+// diffItems compares two item persistences
+func diffItems(oldItems, newItems *wowitem.Persistence) itemDiff {
+	d := itemDiff{Added: []int64{}, Removed: []int64{}, Changed: []fieldChange{}}
+
+	for _, id := range oldItems.Keys() {
+		if _, ok := newItems.Persistence.Get(id); !ok {
+			d.Removed = append(d.Removed, id)
+		}
+	}
+
+	for _, id := range newItems.Keys() {
+		newItem, _ := newItems.Persistence.Get(id)
+		oldItem, ok := oldItems.Persistence.Get(id)
+		if !ok {
+			d.Added = append(d.Added, id)
+			continue
+		}
+
+		d.Changed = append(d.Changed, itemChanges(oldItem, newItem)...)
+	}
+
+	return d
+}
+
+// itemChanges returns the fields that differ between two copies of an item
+func itemChanges(oldItem, newItem wowitem.Item) []fieldChange {
+	changes := []fieldChange{}
+
+	for _, field := range diffFields {
+		oldValue, newValue := field.value(oldItem), field.value(newItem)
+		if oldValue != newValue {
+			changes = append(changes, fieldChange{ID: newItem.ID(), Name: newItem.Name(), Field: field.name, Old: oldValue, New: newValue})
+		}
+	}
+
+	return changes
+}
+
+// printDiff writes the differences as a table
+func printDiff(w io.Writer, d itemDiff, oldItems, newItems *wowitem.Persistence) {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "Change\tID\tField\tOld\tNew\tName")
+	fmt.Fprintln(writer, "------\t--\t-----\t---\t---\t----")
+
+	for _, id := range d.Removed {
+		item, _ := oldItems.Persistence.Get(id)
+		fmt.Fprintf(writer, "removed\t%d\t\t\t\t%s\n", id, item.Name())
+	}
+	for _, id := range d.Added {
+		item, _ := newItems.Persistence.Get(id)
+		fmt.Fprintf(writer, "added\t%d\t\t\t\t%s\n", id, item.Name())
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(writer, "changed\t%d\t%s\t%s\t%s\t%s\n", c.ID, c.Field, c.Old, c.New, c.Name)
+	}
+
+	writer.Flush()
+
+	fmt.Fprintf(w, "\n%d added, %d removed, %d field changes\n", len(d.Added), len(d.Removed), len(d.Changed))
+}
+
+// loadItems loads an item persistence from exactly the file named, or from its .gob
+// file if it is named without the suffix
+func loadItems(filename string) (*wowitem.Persistence, error) {
+	for _, name := range []string{filename, filename + ".gob"} {
+		info, err := os.Stat(name)
+		if err == nil && !info.IsDir() {
+			return wowitem.Open(name)
+		}
+	}
+
+	return nil, fmt.Errorf("no item persistence file %s or %s.gob", filename, filename)
+}
+
+func runDiff(args []string, paths *path.Paths) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+
+	format := flags.String("format", "table", "output format {table, json}")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		usage()
+		return fmt.Errorf("diff requires an old and a new persistence file")
+	}
+
+	if !slices.Contains([]string{"table", "json"}, *format) {
+		return fmt.Errorf("format must be one of {table, json} got %s", *format)
+	}
+
+	oldItems, err := loadItems(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	newItems, err := loadItems(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	d := diffItems(oldItems, newItems)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(d)
+	}
+
+	printDiff(os.Stdout, d, oldItems, newItems)
+
+	return nil
+}
@@ -3,13 +3,21 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/erikbryant/wow/internal/appearanceset"
 	"github.com/erikbryant/wow/internal/path"
 	"github.com/erikbryant/wow/internal/persist"
+	"github.com/erikbryant/wow/internal/query"
+	"github.com/erikbryant/wow/internal/wowapi"
 	"github.com/erikbryant/wow/internal/wowitem"
 )
 
@@ -24,6 +32,16 @@ func testPaths(t *testing.T) *path.Paths {
 	return paths
 }
 
+func newItem(t *testing.T, data map[string]any) *wowitem.Item {
+	t.Helper()
+
+	item, err := wowitem.NewItem(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
 func saveItems(t *testing.T, filename string, items ...*wowitem.Item) {
 	t.Helper()
 
@@ -39,10 +57,8 @@ func saveItems(t *testing.T, filename string, items ...*wowitem.Item) {
 func saveAppearances(t *testing.T, filename string, appearances ...int64) {
 	t.Helper()
 
-	p := persist.New[int64, bool](filename)
-	for _, id := range appearances {
-		p.Set(id, true)
-	}
+	p := persist.New[int64, appearanceset.Set](filename)
+	p.Set(1, appearanceset.Set{Name: "Test Set", Appearances: appearances})
 	if err := p.Save(); err != nil {
 		t.Fatalf("save appearances: %v", err)
 	}
@@ -126,7 +142,7 @@ func TestRunCreateRequiresType(t *testing.T) {
 
 func TestDeleteItem(t *testing.T) {
 	paths := testPaths(t)
-	item := wowitem.NewItem(map[string]any{
+	item := newItem(t, map[string]any{
 		"id":           json.Number("123"),
 		"name":         "Test Item",
 		"level":        json.Number("10"),
@@ -164,7 +180,7 @@ func TestRunDeleteRequiresID(t *testing.T) {
 
 func TestJSON(t *testing.T) {
 	paths := testPaths(t)
-	item := wowitem.NewItem(map[string]any{
+	item := newItem(t, map[string]any{
 		"id":           json.Number("123"),
 		"name":         "Test Item",
 		"level":        json.Number("10"),
@@ -199,7 +215,7 @@ func TestRunQueryByNameAndSort(t *testing.T) {
 	paths := testPaths(t)
 
 	items := []*wowitem.Item{
-		wowitem.NewItem(map[string]any{
+		newItem(t, map[string]any{
 			"id":           json.Number("200"),
 			"name":         "Alpha Sword",
 			"level":        json.Number("10"),
@@ -207,7 +223,7 @@ func TestRunQueryByNameAndSort(t *testing.T) {
 			"item_class":   map[string]any{"name": "Weapon"},
 			"preview_item": map[string]any{"quality": map[string]any{"name": "Rare"}},
 		}),
-		wowitem.NewItem(map[string]any{
+		newItem(t, map[string]any{
 			"id":           json.Number("300"),
 			"name":         "Beta Sword",
 			"level":        json.Number("20"),
@@ -233,6 +249,34 @@ func TestRunQueryByNameAndSort(t *testing.T) {
 	}
 }
 
+func TestRunQueryPriceZero(t *testing.T) {
+	paths := testPaths(t)
+	saveItems(t, paths.Items,
+		newItem(t, map[string]any{"id": json.Number("1"), "name": "Worthless Rock", "level": json.Number("1"), "is_stackable": true, "item_class": map[string]any{"name": "Junk"}}),
+		newItem(t, map[string]any{"id": json.Number("2"), "name": "Shiny Rock", "level": json.Number("1"), "is_stackable": true, "item_class": map[string]any{"name": "Junk"},
+			"preview_item": map[string]any{"sell_price": map[string]any{"value": json.Number("500")}}}),
+	)
+	saveAppearances(t, paths.Appearances)
+
+	output := captureStdout(t, func() {
+		if err := runQuery([]string{"-price-max", "0"}, paths); err != nil {
+			t.Fatalf("runQuery() error = %v", err)
+		}
+	})
+	if !strings.Contains(output, "Worthless Rock") || strings.Contains(output, "Shiny Rock") {
+		t.Fatalf("-price-max 0 should select only the unsellable item:\n%s", output)
+	}
+
+	output = captureStdout(t, func() {
+		if err := runQuery(nil, paths); err != nil {
+			t.Fatalf("runQuery() error = %v", err)
+		}
+	})
+	if !strings.Contains(output, "Worthless Rock") || !strings.Contains(output, "Shiny Rock") {
+		t.Fatalf("no price flags should select every item:\n%s", output)
+	}
+}
+
 func TestRunQueryInvalidSort(t *testing.T) {
 	paths := testPaths(t)
 	saveItems(t, paths.Items)
@@ -242,3 +286,458 @@ func TestRunQueryInvalidSort(t *testing.T) {
 		t.Fatalf("runQuery() error = %v", err)
 	}
 }
+
+func TestPrintSets(t *testing.T) {
+	completions := []appearanceset.Completion{
+		{SetID: 1, Name: "Done Set", Owned: 4, Total: 4},
+		{SetID: 2, Name: "Nearly Set", Owned: 3, Total: 4},
+		{SetID: 3, Name: "Barely Set", Owned: 1, Total: 4},
+	}
+
+	var b bytes.Buffer
+	printSets(&b, completions, 50, false)
+	output := b.String()
+
+	if !strings.Contains(output, "Nearly Set") || !strings.Contains(output, "75.0%") || !strings.Contains(output, "3/4") {
+		t.Fatalf("missing nearly complete set: \n%s", output)
+	}
+	if strings.Contains(output, "Done Set") || strings.Contains(output, "Barely Set") {
+		t.Fatalf("unexpected set shown: \n%s", output)
+	}
+
+	b.Reset()
+	printSets(&b, completions, 0, true)
+	if !strings.Contains(b.String(), "Done Set") || !strings.Contains(b.String(), "Barely Set") {
+		t.Fatalf("wanted all sets: \n%s", b.String())
+	}
+}
+
+func TestRunQueryInvalidWhere(t *testing.T) {
+	paths := testPaths(t)
+	saveItems(t, paths.Items)
+	saveAppearances(t, paths.Appearances)
+
+	if err := runQuery([]string{"-where", "rare and"}, paths); err == nil || !strings.Contains(err.Error(), "invalid -where") {
+		t.Fatalf("runQuery() error = %v", err)
+	}
+}
+
+func TestRunQuerySortLimit(t *testing.T) {
+	paths := testPaths(t)
+	items := []*wowitem.Item{}
+	for id, name := range map[int64]string{1: "Alpha Sword", 2: "Beta Sword", 3: "Gamma Sword"} {
+		items = append(items, &wowitem.Item{XID: id, XItem: map[string]any{
+			"name":         name,
+			"level":        json.Number("10"),
+			"is_stackable": false,
+			"item_class":   map[string]any{"name": "Weapon"},
+		}})
+	}
+	saveItems(t, paths.Items, items...)
+	saveAppearances(t, paths.Appearances)
+
+	output := captureStdout(t, func() {
+		if err := runQuery([]string{"-sort", "-name", "-limit", "2"}, paths); err != nil {
+			t.Fatalf("runQuery() error = %v", err)
+		}
+	})
+
+	if strings.Contains(output, "Alpha Sword") || !strings.Contains(output, "Beta Sword") || !strings.Contains(output, "Gamma Sword") {
+		t.Fatalf("wanted Gamma and Beta only: \n%q\n", output)
+	}
+	if strings.Index(output, "Beta Sword") < strings.Index(output, "Gamma Sword") {
+		t.Fatalf("output sorted incorrectly: \n%q\n", output)
+	}
+}
+
+func TestRunQuerySearch(t *testing.T) {
+	paths := testPaths(t)
+	items := []*wowitem.Item{}
+	for id, name := range map[int64]string{1: "Sword of the Tyrhold", 2: "Tyrhold Broadsword", 3: "Kickback 5000"} {
+		items = append(items, &wowitem.Item{XID: id, XItem: map[string]any{
+			"name":         name,
+			"level":        json.Number("10"),
+			"is_stackable": false,
+			"item_class":   map[string]any{"name": "Weapon"},
+		}})
+	}
+	saveItems(t, paths.Items, items...)
+	saveAppearances(t, paths.Appearances)
+
+	output := captureStdout(t, func() {
+		if err := runQuery([]string{"-search", "tyrhold brodsword"}, paths); err != nil {
+			t.Fatalf("runQuery() error = %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Tyrhold Broadsword") || strings.Contains(output, "Kickback") || strings.Contains(output, "Sword of the Tyrhold") {
+		t.Fatalf("wanted only the broadsword: \n%q\n", output)
+	}
+}
+
+func statsItem(id int64, name, class, subclass, quality string, price int64, updated time.Time) wowitem.Item {
+	return wowitem.Item{XID: id, XUpdated: updated, XItem: map[string]any{
+		"name":          name,
+		"level":         json.Number("10"),
+		"is_stackable":  false,
+		"item_class":    map[string]any{"name": class},
+		"item_subclass": map[string]any{"name": subclass},
+		"preview_item": map[string]any{
+			"quality":    map[string]any{"name": quality},
+			"sell_price": map[string]any{"value": json.Number(strconv.FormatInt(price, 10))},
+		},
+	}}
+}
+
+func TestCollectStats(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	items := []wowitem.Item{
+		statsItem(1, "Cheap Sword", "Weapon", "Swords", "Rare", 100, now.Add(-time.Hour)),
+		statsItem(2, "Dear Sword", "Weapon", "Swords", "Epic", 90000, now.Add(-10*24*time.Hour)),
+		statsItem(3, "Free Helm", "Armor", "Plate", "Rare", 0, now.Add(-400*24*time.Hour)),
+	}
+	items[2].XItem["provenance"] = map[string]any{"type": wowitem.ProvenanceSynthetic}
+
+	s := collectStats(items, now, 2)
+
+	if s.total != 3 || s.synthetic != 1 || s.noSellPrice != 1 {
+		t.Fatalf("total=%d synthetic=%d noSellPrice=%d", s.total, s.synthetic, s.noSellPrice)
+	}
+	if s.byClass["Weapon"] != 2 || s.bySubclass["Armor / Plate"] != 1 || s.byQuality["Rare"] != 2 {
+		t.Fatalf("byClass=%v bySubclass=%v byQuality=%v", s.byClass, s.bySubclass, s.byQuality)
+	}
+	if want := []int{1, 0, 1, 0, 0, 1}; !slices.Equal(s.byAge, want) {
+		t.Fatalf("byAge=%v, want %v", s.byAge, want)
+	}
+	if len(s.topPrices) != 2 || s.topPrices[0].ID() != 2 || s.topPrices[1].ID() != 1 {
+		t.Fatalf("topPrices=%v", s.topPrices)
+	}
+
+	var b bytes.Buffer
+	printStats(&b, s)
+	for _, want := range []string{"Items:", "Synthetic items:", "By class / subclass", "Weapon / Swords", "< 30 days", "9.00.00", "Dear Sword"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("stats missing %q: \n%s", want, b.String())
+		}
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	saveItems(t, dir+"/old",
+		ptr(statsItem(1, "Kept Sword", "Weapon", "Swords", "Rare", 100, now)),
+		ptr(statsItem(2, "Gone Helm", "Armor", "Plate", "Rare", 100, now)),
+	)
+	saveItems(t, dir+"/new",
+		ptr(statsItem(1, "Kept Sword", "Weapon", "Swords", "Rare", 250, now)),
+		ptr(statsItem(3, "New Boots", "Armor", "Plate", "Epic", 100, now)),
+	)
+
+	output := captureStdout(t, func() {
+		if err := runDiff([]string{dir + "/old.gob", dir + "/new"}, testPaths(t)); err != nil {
+			t.Fatalf("runDiff() error = %v", err)
+		}
+	})
+	for _, want := range []string{"removed", "Gone Helm", "added", "New Boots", "sell price", "0.01.00", "0.02.50", "1 added, 1 removed, 1 field changes"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("diff missing %q: \n%s", want, output)
+		}
+	}
+
+	output = captureStdout(t, func() {
+		if err := runDiff([]string{"-format", "json", dir + "/old", dir + "/new"}, testPaths(t)); err != nil {
+			t.Fatalf("runDiff() error = %v", err)
+		}
+	})
+	var d itemDiff
+	if err := json.Unmarshal([]byte(output), &d); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v: %s", err, output)
+	}
+	if !slices.Equal(d.Added, []int64{3}) || !slices.Equal(d.Removed, []int64{2}) || len(d.Changed) != 1 || d.Changed[0].Field != "sell price" {
+		t.Fatalf("diff = %+v", d)
+	}
+
+	// A backup is opened by its exact name
+	if err := os.Rename(dir+"/old.gob", dir+"/items.gob.bak"); err != nil {
+		t.Fatal(err)
+	}
+	output = captureStdout(t, func() {
+		if err := runDiff([]string{dir + "/items.gob.bak", dir + "/new.gob"}, testPaths(t)); err != nil {
+			t.Fatalf("runDiff() error = %v", err)
+		}
+	})
+	if !strings.Contains(output, "1 added, 1 removed, 1 field changes") {
+		t.Errorf("diff of backup: \n%s", output)
+	}
+
+	err := runDiff([]string{dir + "/missing", dir + "/new"}, testPaths(t))
+	if err == nil || !strings.Contains(err.Error(), "no item persistence file") {
+		t.Fatalf("runDiff(missing) error = %v", err)
+	}
+}
+
+func ptr(item wowitem.Item) *wowitem.Item {
+	return &item
+}
+
+func TestStaleItems(t *testing.T) {
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	wowItems := wowitem.NewEmpty(t.TempDir() + "/items")
+	for _, item := range []wowitem.Item{
+		statsItem(1, "Cheap Sword", "Weapon", "Swords", "Rare", 100, old),
+		statsItem(2, "Dear Sword", "Weapon", "Swords", "Epic", 90000, old),
+		statsItem(3, "Fresh Sword", "Weapon", "Swords", "Epic", 90000, time.Now()),
+		statsItem(4, "Dear Helm", "Armor", "Plate", "Rare", 50000, old),
+	} {
+		wowItems.Set(item.ID(), item)
+	}
+
+	if got, want := staleItems(wowItems, 7*24*time.Hour, nil), []int64{2, 4, 1}; !slices.Equal(got, want) {
+		t.Fatalf("staleItems() = %v, want %v", got, want)
+	}
+	if got, want := staleItems(wowItems, 7*24*time.Hour, []query.Predicate{query.ItemClass("Weapon")}), []int64{2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("staleItems(Weapon) = %v, want %v", got, want)
+	}
+
+	synthetic := statsItem(5, "Made Up Sword", "Weapon", "Swords", "Epic", 99000, old)
+	synthetic.XItem["provenance"] = map[string]any{"type": wowitem.ProvenanceSynthetic}
+	wowItems.Set(synthetic.ID(), synthetic)
+
+	if got, want := staleItems(wowItems, 7*24*time.Hour, refreshOptions{}.filters()), []int64{2, 4, 1}; !slices.Equal(got, want) {
+		t.Fatalf("staleItems(skip synthetic) = %v, want %v", got, want)
+	}
+	if got, want := staleItems(wowItems, 7*24*time.Hour, refreshOptions{includeSynthetic: true}.filters()), []int64{5, 2, 4, 1}; !slices.Equal(got, want) {
+		t.Fatalf("staleItems(include synthetic) = %v, want %v", got, want)
+	}
+}
+
+func TestRefreshItems(t *testing.T) {
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	filename := t.TempDir() + "/items"
+	saveItems(t, filename,
+		ptr(statsItem(1, "Sword", "Tradeskill", "Herb", "Rare", 100, old)),
+		ptr(statsItem(2, "Helm", "Tradeskill", "Herb", "Rare", 100, old)),
+		ptr(statsItem(3, "Boots", "Tradeskill", "Herb", "Rare", 100, old)),
+	)
+	wowItems, err := wowitem.New(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := func(id int64) (wowitem.Item, error) {
+		if id == 2 {
+			return wowitem.Item{}, errors.New("not found")
+		}
+		return statsItem(id, "Refreshed", "Tradeskill", "Herb", "Rare", 200, time.Now()), nil
+	}
+
+	checkpoints := 0
+	checkpointed := func(r *refreshResult) error {
+		checkpoints++
+		if r.refreshed != checkpoints {
+			t.Errorf("checkpoint %d after %d items", checkpoints, r.refreshed)
+		}
+		return nil
+	}
+
+	r, err := refreshItems(wowItems, []int64{1, 2, 3}, fetch, 2, 1, checkpointed)
+	if err != nil || r.refreshed != 2 || checkpoints != 2 {
+		t.Fatalf("refreshItems() = %d, %v after %d checkpoints, want 2, nil after 2", r.refreshed, err, checkpoints)
+	}
+	slices.Sort(r.exportChanged)
+	if !slices.Equal(r.exportChanged, []int64{1, 3}) {
+		t.Fatalf("exportChanged = %v, want [1 3]", r.exportChanged)
+	}
+	priceChanges := 0
+	for _, c := range r.changes {
+		if c.Field == "sell price" {
+			priceChanges++
+		}
+	}
+	if priceChanges != 2 {
+		t.Fatalf("changes = %+v, want 2 sell price changes", r.changes)
+	}
+
+	// A failed fetch keeps the old record
+	if item, ok := wowItems.Persistence.Get(2); !ok || item.Name() != "Helm" {
+		t.Fatalf("item 2 = %v, %v, want old record", item, ok)
+	}
+
+	// Checkpoints saved the refreshed items without a final save
+	saved, err := wowitem.New(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshedOnDisk := 0
+	for _, item := range saved.Values() {
+		if item.Name() == "Refreshed" {
+			refreshedOnDisk++
+		}
+	}
+	if refreshedOnDisk != 2 {
+		t.Fatalf("%d refreshed items on disk, want 2", refreshedOnDisk)
+	}
+}
+
+func TestRefreshAllFailedCheckpoint(t *testing.T) {
+	paths := testPaths(t)
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	saveItems(t, paths.Items,
+		ptr(statsItem(1, "Sword", "Tradeskill", "Herb", "Rare", 100, old)),
+		ptr(statsItem(2, "Helm", "Tradeskill", "Herb", "Rare", 100, old)),
+		ptr(statsItem(3, "Boots", "Tradeskill", "Herb", "Rare", 100, old)),
+	)
+
+	// A directory where the checkpoint writes its temp file makes the checkpoint fail
+	tmp := paths.Items + ".gob.tmp"
+	if err := os.Mkdir(tmp, 0700); err != nil {
+		t.Fatal(err)
+	}
+	fetch := func(id int64) (wowitem.Item, error) {
+		if id == 3 {
+			// The first checkpoint is done by now (item 2 was received); let the final save succeed
+			if err := os.Remove(tmp); err != nil {
+				t.Error(err)
+			}
+		}
+		return statsItem(id, "Refreshed", "Tradeskill", "Herb", "Rare", 200, time.Now()), nil
+	}
+
+	opts := refreshOptions{maxRefresh: 10, maxAge: 7 * 24 * time.Hour, workers: 1, checkpoint: 1, fetch: fetch}
+	var err error
+	captureStdout(t, func() { err = refreshAll(opts, paths) })
+	if err == nil || !strings.Contains(err.Error(), "checkpoint") {
+		t.Fatalf("refreshAll() error = %v, want checkpoint error", err)
+	}
+
+	// Every item was saved by the final save, so all of them are logged and marked stale
+	stale, err := os.ReadFile(paths.PriceCacheStale)
+	if err != nil || string(stale) != "1\n2\n3\n" {
+		t.Fatalf("stale marker = %q, %v", stale, err)
+	}
+	changeLog, err := os.ReadFile(paths.ItemChanges)
+	if err != nil || strings.Count(string(changeLog), "sell price") != 3 {
+		t.Fatalf("change log = %q, %v", changeLog, err)
+	}
+}
+
+func TestRunRefreshNegativeMax(t *testing.T) {
+	paths := testPaths(t)
+	if err := runRefresh([]string{"-max-refresh", "-1"}, paths); err == nil || !strings.Contains(err.Error(), "max-refresh") {
+		t.Fatalf("runRefresh() error = %v", err)
+	}
+}
+
+func TestRefreshReport(t *testing.T) {
+	paths := testPaths(t)
+	wowItems := wowitem.NewEmpty(paths.Items)
+	wowItems.Set(1, statsItem(1, "Sword", "Tradeskill", "Herb", "Rare", 50000, time.Now()))
+
+	r := refreshResult{}
+	r.record(wowItems, statsItem(1, "Sword", "Tradeskill", "Herb", "Rare", 60000, time.Now()))
+	r.record(wowItems, statsItem(2, "New Sword", "Tradeskill", "Herb", "Rare", 60000, time.Now()))
+
+	output := captureStdout(t, func() {
+		if err := r.report(paths, wowItems, false); err != nil {
+			t.Fatalf("report() error = %v", err)
+		}
+	})
+	if !strings.Contains(output, "1 items changed, 1 sell price") {
+		t.Fatalf("summary = %q", output)
+	}
+
+	changeLog, err := os.ReadFile(paths.ItemChanges)
+	if err != nil || !strings.Contains(string(changeLog), "sell price   5.00.00 -> 6.00.00  Sword") {
+		t.Fatalf("change log = %q, %v", changeLog, err)
+	}
+	stale, err := os.ReadFile(paths.PriceCacheStale)
+	if err != nil || string(stale) != "1\n" {
+		t.Fatalf("stale marker = %q, %v", stale, err)
+	}
+
+	// A second refresh before wow runs adds to the marker rather than replacing it
+	if stale, err := updateExport(paths, wowItems, []int64{3, 1}, false); err != nil || stale != 2 {
+		t.Fatalf("updateExport() = %d, %v, want 2, nil", stale, err)
+	}
+	stale, err = os.ReadFile(paths.PriceCacheStale)
+	if err != nil || string(stale) != "1\n3\n" {
+		t.Fatalf("merged stale marker = %q, %v", stale, err)
+	}
+
+	// Reporting again writes nothing new
+	captureStdout(t, func() {
+		if err := r.report(paths, wowItems, false); err != nil {
+			t.Fatalf("report() error = %v", err)
+		}
+	})
+	changeLogAgain, err := os.ReadFile(paths.ItemChanges)
+	if err != nil || string(changeLogAgain) != string(changeLog) {
+		t.Fatalf("change log after second report = %q, %v", changeLogAgain, err)
+	}
+
+	r = refreshResult{}
+	r.record(wowItems, statsItem(1, "Sword", "Tradeskill", "Herb", "Rare", 60000, time.Now()))
+	output = captureStdout(t, func() {
+		if err := r.report(paths, wowItems, true); err != nil {
+			t.Fatalf("report() error = %v", err)
+		}
+	})
+	if !strings.Contains(output, "Regenerated") {
+		t.Fatalf("report = %q", output)
+	}
+	if _, err := os.Stat(paths.PriceCache); err != nil {
+		t.Fatalf("price cache not regenerated: %v", err)
+	}
+	if _, err := os.Stat(paths.PriceCacheStale); !os.IsNotExist(err) {
+		t.Fatalf("stale marker not removed: %v", err)
+	}
+}
+
+func TestCheckSynthetics(t *testing.T) {
+	now := time.Now()
+	synthetic := []wowitem.Item{
+		statsItem(1, "Still Missing", "Tradeskill", "Other", "Common", 100, now),
+		statsItem(2, "Now Real", "Tradeskill", "Other", "Common", 100, now),
+		statsItem(3, "Real Price Differs", "Tradeskill", "Other", "Common", 100, now),
+		statsItem(4, "Unreachable", "Tradeskill", "Other", "Common", 100, now),
+	}
+
+	fetch := func(id int64) (wowitem.Item, error) {
+		switch id {
+		case 2:
+			return statsItem(2, "Now Real", "Tradeskill", "Other", "Common", 100, now), nil
+		case 3:
+			return statsItem(3, "Real Price Differs", "Tradeskill", "Other", "Common", 250, now), nil
+		case 4:
+			return wowitem.Item{}, errors.New("connection refused")
+		default:
+			return wowitem.Item{}, fmt.Errorf("Item: HTTP status 404: %w", wowapi.ErrNotFound)
+		}
+	}
+
+	statuses := checkSynthetics(synthetic, fetch)
+	if len(statuses) != 4 {
+		t.Fatalf("got %d statuses, want 4", len(statuses))
+	}
+	if statuses[0].real != nil || statuses[0].err != nil {
+		t.Errorf("item 1 = %+v, want still missing", statuses[0])
+	}
+	if statuses[1].real == nil || statuses[1].priceDiffers() {
+		t.Errorf("item 2 = %+v, want real at the same price", statuses[1])
+	}
+	if statuses[2].real == nil || !statuses[2].priceDiffers() {
+		t.Errorf("item 3 = %+v, want real at a different price", statuses[2])
+	}
+	if statuses[3].err == nil {
+		t.Errorf("item 4 = %+v, want error", statuses[3])
+	}
+
+	var b bytes.Buffer
+	printSyntheticCheck(&b, statuses)
+	for _, want := range []string{"missing", "real", "price differs", "connection refused", "1 still missing from the web API, 2 now real (1 at a different price), 1 could not be checked"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, b.String())
+		}
+	}
+}
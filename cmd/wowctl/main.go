@@ -14,19 +14,41 @@ func usage() {
 Commands:
   create {appearance|item}        Create a new persistence
   delete -id <id>                 Delete persisted item
+  diff [-format=table] old new    Show what changed between two item persistences
   json -id <id>                   Show JSON for an item
   query [options]                 Search for items
   refresh [-max-refresh=1000]     Refresh stale items
+  refresh appearances             Fetch new appearance sets
+  sets [-min-percent=0] [-include-complete]
+                                  Show appearance set completion
+  stats [-top=10]                 Summarize the item persistence
   synthetic {list|populate}       Manage synthetic items
+  synthetic add -id <id> -name <name> [-price=0] [-level=1] [-commodity]
+                                  Add a synthetic item
+  synthetic remove -id <id>       Remove a synthetic item
+  synthetic check [-replace]      Report synthetic items the web API now has
   help                            Display this help message
 
 Examples:
   wowctl delete -id 12345
+  wowctl diff -format=json data/items.gob data/items.new.gob
   wowctl json -id 12345
   wowctl query
   wowctl query -rare -in-appearance-set
+  wowctl query -synthetic
+  wowctl query -sort=class,-price -limit 20
+  wowctl query -search "tyrhold brodsword"
+  wowctl query -format=csv -columns=id,name,price
+  wowctl query -where 'quality in (Rare,Epic) and class = "Armor" and not cosmetic'
   wowctl refresh -max-refresh=42
+  wowctl refresh -max-age=72h -class=Weapon -workers=8
   wowctl refresh -id 12345
+  wowctl refresh -id 12345 -include-synthetic
+  wowctl refresh appearances
+  wowctl sets -min-percent=75
+  wowctl sets -include-complete
+  wowctl stats
+  wowctl synthetic add -id 268950 -name "Bill of Sale" -price 2500 -level 10
   `)
 }
 
@@ -53,12 +75,18 @@ func main() {
 		err = runCreate(args, paths)
 	case "delete":
 		err = runDelete(args, paths)
+	case "diff":
+		err = runDiff(args, paths)
 	case "json":
 		err = runJSON(args, paths)
 	case "query":
 		err = runQuery(args, paths)
 	case "refresh":
 		err = runRefresh(args, paths)
+	case "sets":
+		err = runSets(args, paths)
+	case "stats":
+		err = runStats(args, paths)
 	case "synthetic":
 		err = runSynthetic(args, paths)
 	case "help":
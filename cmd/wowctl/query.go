@@ -1,9 +1,12 @@
 package main
 
 import (
+	"cmp"
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/erikbryant/wow/internal/appearanceset"
 	"github.com/erikbryant/wow/internal/output"
@@ -63,16 +66,133 @@ func runQuery(args []string, paths *path.Paths) error {
 		"item ID",
 	)
 
+	priceMin := flags.Int64(
+		"price-min",
+		-1,
+		"minimum vendor sell price, in coppers (-1 for no minimum)",
+	)
+
+	priceMax := flags.Int64(
+		"price-max",
+		-1,
+		"maximum vendor sell price, in coppers (-1 for no maximum)",
+	)
+
+	subclass := flags.String(
+		"subclass",
+		"",
+		"only items of this subclass",
+	)
+
+	slot := flags.String(
+		"slot",
+		"",
+		"only items of this inventory type (e.g. HEAD)",
+	)
+
+	binding := flags.String(
+		"binding",
+		"",
+		"only items with this binding (e.g. ON_EQUIP)",
+	)
+
+	stackable := flags.Bool(
+		"stackable",
+		false,
+		"only stackable items",
+	)
+
+	equippable := flags.Bool(
+		"equippable",
+		false,
+		"only equippable items",
+	)
+
+	cosmetic := flags.Bool(
+		"cosmetic",
+		false,
+		"only cosmetic items",
+	)
+
+	synthetic := flags.Bool(
+		"synthetic",
+		false,
+		"only synthetic items",
+	)
+
+	toy := flags.Bool(
+		"toy",
+		false,
+		"only toys",
+	)
+
+	variableILevel := flags.Bool(
+		"variable-ilevel",
+		false,
+		"only items whose item level can change",
+	)
+
+	requires := flags.String(
+		"requires",
+		"",
+		"only items whose skill requirement contains this text (e.g. Blacksmithing)",
+	)
+
+	stale := flags.Duration(
+		"stale",
+		0,
+		"only items last updated longer ago than this (e.g. 168h)",
+	)
+
+	search := flags.String(
+		"search",
+		"",
+		"items whose name matches these words, allowing prefixes and typos; best match first unless -sort is given",
+	)
+
+	where := flags.String(
+		"where",
+		"",
+		`filter expression, e.g. 'quality in (Rare,Epic) and class = "Armor" and not cosmetic'`,
+	)
+
 	sortField := flags.String(
 		"sort",
 		"id",
-		"sort items by {id, name}",
+		"comma-separated sort keys {id, equips, stacks, appset, price, ilevel, class, quality, updated, provenance, name}; prefix with - to reverse",
+	)
+
+	format := flags.String(
+		"format",
+		"table",
+		"output format {table, json, jsonl, csv, markdown}; json, jsonl and csv give prices in coppers",
+	)
+
+	columnList := flags.String(
+		"columns",
+		"",
+		"comma-separated columns to show, in order {id, equips, stacks, appset, price, ilevel, class, quality, updated, provenance, name}; default all",
+	)
+
+	limit := flags.Int(
+		"limit",
+		0,
+		"show at most this many items, 0 for all",
 	)
 
 	if err := flags.Parse(args); err != nil {
 		return err
 	}
 
+	if !slices.Contains(output.Formats, *format) {
+		return fmt.Errorf("format must be one of {%s} got %s", strings.Join(output.Formats, ", "), *format)
+	}
+
+	columns, err := output.Columns(*columnList)
+	if err != nil {
+		return err
+	}
+
 	as, err := appearanceset.New(paths.Appearances)
 	if err != nil {
 		return err
@@ -112,25 +232,106 @@ func runQuery(args []string, paths *path.Paths) error {
 		predicates = append(predicates, query.ItemID(*itemID))
 	}
 
+	if *priceMin != -1 {
+		predicates = append(predicates, query.SellPriceAtLeast(*priceMin))
+	}
+
+	if *priceMax != -1 {
+		predicates = append(predicates, query.SellPriceAtMost(*priceMax))
+	}
+
+	if *subclass != "" {
+		predicates = append(predicates, query.ItemSubclass(*subclass))
+	}
+
+	if *slot != "" {
+		predicates = append(predicates, query.InventoryType(*slot))
+	}
+
+	if *binding != "" {
+		predicates = append(predicates, query.Binding(*binding))
+	}
+
+	if *stackable {
+		predicates = append(predicates, query.Stackable())
+	}
+
+	if *equippable {
+		predicates = append(predicates, query.Equippable())
+	}
+
+	if *cosmetic {
+		predicates = append(predicates, query.Cosmetic())
+	}
+
+	if *synthetic {
+		predicates = append(predicates, query.Synthetic())
+	}
+
+	if *toy {
+		predicates = append(predicates, query.Toy())
+	}
+
+	if *variableILevel {
+		predicates = append(predicates, query.VariableItemLevel())
+	}
+
+	if *requires != "" {
+		predicates = append(predicates, query.RequiresProfession(*requires))
+	}
+
+	if *stale != 0 {
+		predicates = append(predicates, query.Stale(*stale))
+	}
+
+	if *where != "" {
+		predicate, err := query.Parse(*where)
+		if err != nil {
+			return fmt.Errorf("invalid -where: %w", err)
+		}
+		predicates = append(predicates, predicate)
+	}
+
 	wowItems, err := wowitem.New(paths.Items)
 	if err != nil {
 		return err
 	}
 
+	sortGiven := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "sort" {
+			sortGiven = true
+		}
+	})
+
+	rank := map[int64]int{}
+	if *search != "" {
+		for i, match := range wowItems.SearchNames(*search) {
+			rank[match.ID] = i
+		}
+		predicates = append(predicates, func(item wowitem.Item) bool {
+			_, ok := rank[item.ID()]
+			return ok
+		})
+	}
+
 	items := wowItems.Values()
 
 	results := query.Find(items, predicates...)
 
-	switch *sortField {
-	case "id":
-		query.Sort(results, query.ByID)
-	case "name":
-		query.Sort(results, query.ByName)
-	default:
-		return fmt.Errorf("sort order must be one of {id, name} got %s", *sortField)
+	if *search != "" && !sortGiven {
+		query.SortBy(results, func(a, b wowitem.Item) int { return cmp.Compare(rank[a.ID()], rank[b.ID()]) })
+	} else {
+		compare, err := query.ParseSort(*sortField, as)
+		if err != nil {
+			return err
+		}
+		query.SortBy(results, compare)
 	}
 
-	output.Table(os.Stdout, results, as)
+	if *limit > 0 && len(results) > *limit {
+		results = results[:*limit]
+	}
 
-	return nil
+	return output.Write(os.Stdout, *format, results, as, columns)
 }
@@ -1,42 +1,51 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/erikbryant/wow/internal/appearanceset"
 	"github.com/erikbryant/wow/internal/output"
 	"github.com/erikbryant/wow/internal/path"
+	"github.com/erikbryant/wow/internal/query"
 	"github.com/erikbryant/wow/internal/wowapi"
 	"github.com/erikbryant/wow/internal/wowitem"
 )
 
-// deleteAndGetNew removes the item from the persistence. Even if we later fail to retrieve
-// new data, at least we got rid of stale data.
-func deleteAndGetNew(wowItems *wowitem.Persistence, itemID int64) (wowitem.Item, error) {
-	wowItems.Delete(itemID)
-	return wowItems.GetLive(itemID)
-}
-
-// refreshItem refreshes a single item
-func refreshItem(itemID int64, paths *path.Paths) error {
+// refreshItem refreshes a single item. The old record is kept if the fetch fails.
+func refreshItem(itemID int64, regenerate, includeSynthetic bool, paths *path.Paths) error {
 	wowItems, err := wowitem.New(paths.Items)
 	if err != nil {
 		return err
 	}
 
-	iNew, errGetNew := deleteAndGetNew(wowItems, itemID)
+	if iOld, ok := wowItems.Persistence.Get(itemID); ok && iOld.Synthetic() && !includeSynthetic {
+		return fmt.Errorf("item %d is synthetic; use -include-synthetic to replace it with web API data", itemID)
+	}
+
+	iNew, err := wowitem.Fetch(itemID)
+	if err != nil {
+		return fmt.Errorf("could not retrieve itemID %d: %w", itemID, err)
+	}
+
+	r := refreshResult{}
+	r.record(wowItems, iNew)
+	wowItems.Set(itemID, iNew)
 
-	// Persist the deletion even if getting the new data fails
 	err = wowItems.Save()
 	if err != nil {
 		return fmt.Errorf("failed to save item persistence: %w", err)
 	}
 
-	if errGetNew != nil {
-		return fmt.Errorf("could not retrieve itemID %d: %w", itemID, errGetNew)
+	err = r.report(paths, wowItems, regenerate)
+	if err != nil {
+		return err
 	}
 
 	as, err := appearanceset.New(paths.Appearances)
@@ -49,38 +58,234 @@ func refreshItem(itemID int64, paths *path.Paths) error {
 	return nil
 }
 
-// refreshAll refreshes persisted items older than a certain age
-func refreshAll(maxRefresh int, paths *path.Paths) error {
-	maxAge := 24 * time.Hour * 7 // 1 week
-	needsRefresh := 0
-	refreshCount := 0
+// refreshOptions control which items refreshAll refreshes and how
+type refreshOptions struct {
+	maxRefresh int
+	regenerate bool
+	maxAge     time.Duration
+	predicates []query.Predicate
+	workers    int
+	checkpoint int
+	fetch      func(int64) (wowitem.Item, error)
 
-	wowItems, err := wowitem.New(paths.Items)
+	// includeSynthetic refreshes synthetic items too, replacing them if the web API now has them
+	includeSynthetic bool
+}
+
+// filters returns the predicates an item must satisfy to be refreshed
+func (opts refreshOptions) filters() []query.Predicate {
+	predicates := slices.Clone(opts.predicates)
+	if !opts.includeSynthetic {
+		predicates = append(predicates, query.Not(query.Synthetic()))
+	}
+	return predicates
+}
+
+// staleItems returns the IDs of the items that need refreshing, most valuable first
+func staleItems(wowItems *wowitem.Persistence, maxAge time.Duration, predicates []query.Predicate) []int64 {
+	stale := query.Find(wowItems.Values(), append([]query.Predicate{query.Stale(maxAge)}, predicates...)...)
+
+	query.SortBy(stale, func(a, b wowitem.Item) int {
+		return cmp.Or(
+			cmp.Compare(b.SellPriceAdvertised(), a.SellPriceAdvertised()),
+			cmp.Compare(a.ID(), b.ID()),
+		)
+	})
+
+	ids := []int64{}
+	for _, item := range stale {
+		ids = append(ids, item.ID())
+	}
+
+	return ids
+}
+
+// fetchResult is the outcome of fetching one item
+type fetchResult struct {
+	id   int64
+	item wowitem.Item
+	err  error
+}
+
+// refreshResult records what a refresh changed
+type refreshResult struct {
+	refreshed int
+	changes   []fieldChange
+
+	// exportChanged holds the IDs of items whose price cache entry changed
+	exportChanged []int64
+
+	// logged and exported count the changes and exportChanged IDs already written by flush
+	logged   int
+	exported int
+	// stale is the number of items the price cache is out of date for, as of the last flush
+	stale int
+}
+
+// record notes how newItem differs from the persisted copy it is about to replace
+func (r *refreshResult) record(wowItems *wowitem.Persistence, newItem wowitem.Item) {
+	r.refreshed++
+
+	oldItem, ok := wowItems.Persistence.Get(newItem.ID())
+	if !ok {
+		return
+	}
+
+	r.changes = append(r.changes, itemChanges(oldItem, newItem)...)
+	if exportChanged(oldItem, newItem) {
+		r.exportChanged = append(r.exportChanged, newItem.ID())
+	}
+}
+
+// flush logs the changes recorded since the last flush and brings the wowMerchant export
+// up to date for them. Call it each time the items are saved, so that what is on disk
+// is never missing from the change log or the price cache stale marker.
+func (r *refreshResult) flush(paths *path.Paths, wowItems *wowitem.Persistence, regenerate bool) error {
+	err := appendChangeLog(paths.ItemChanges, r.changes[r.logged:], time.Now())
 	if err != nil {
 		return err
 	}
+	r.logged = len(r.changes)
 
-	for _, i := range wowItems.Values() {
-		if i.Stale(maxAge) {
-			needsRefresh++
-			if refreshCount < maxRefresh {
-				_, err := deleteAndGetNew(wowItems, i.ID())
-				if err == nil {
-					refreshCount++
-				} else {
-					fmt.Fprintf(os.Stderr, "could not retrieve itemID %d: %s\n", i.ID(), err)
-					continue
-				}
+	if r.exported == len(r.exportChanged) {
+		return nil
+	}
+	r.stale, err = updateExport(paths, wowItems, r.exportChanged[r.exported:], regenerate)
+	if err != nil {
+		return err
+	}
+	r.exported = len(r.exportChanged)
+
+	return nil
+}
+
+// report flushes and summarizes the changes
+func (r *refreshResult) report(paths *path.Paths, wowItems *wowitem.Persistence, regenerate bool) error {
+	err := r.flush(paths, wowItems, regenerate)
+	if err != nil {
+		return err
+	}
+
+	summarizeChanges(os.Stdout, r.changes)
+
+	switch {
+	case len(r.exportChanged) == 0:
+	case r.stale == 0:
+		fmt.Printf("Regenerated %s\n", paths.PriceCache)
+	default:
+		fmt.Fprintf(os.Stderr, "*** %s is out of date for %d items; rerun wow or 'wowctl refresh -regenerate'\n", paths.PriceCache, r.stale)
+	}
+
+	return nil
+}
+
+// refreshItems fetches the items using a pool of workers. Each item is replaced only once
+// its new record arrives. The persistence is saved every checkpoint items so a crash
+// does not lose all progress, and saved is called after each checkpoint. It returns
+// what the refresh changed.
+func refreshItems(wowItems *wowitem.Persistence, ids []int64, fetch func(int64) (wowitem.Item, error), workers, checkpoint int, saved func(*refreshResult) error) (refreshResult, error) {
+	jobs := make(chan int64)
+	results := make(chan fetchResult)
+
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Go(func() {
+			for id := range jobs {
+				item, err := fetch(id)
+				results <- fetchResult{id: id, item: item, err: err}
+			}
+		})
+	}
+
+	go func() {
+		for _, id := range ids {
+			jobs <- id
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	refreshed := refreshResult{}
+	var saveErr error
+
+	for r := range results {
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "could not retrieve itemID %d: %s\n", r.id, r.err)
+			continue
+		}
+
+		refreshed.record(wowItems, r.item)
+		wowItems.Set(r.id, r.item)
+
+		if checkpoint > 0 && refreshed.refreshed%checkpoint == 0 && saveErr == nil {
+			saveErr = wowItems.Save()
+			if saveErr == nil && saved != nil {
+				saveErr = saved(&refreshed)
 			}
 		}
 	}
 
-	err = wowItems.Save()
+	if saveErr != nil {
+		return refreshed, fmt.Errorf("failed to checkpoint item persistence: %w", saveErr)
+	}
+
+	return refreshed, nil
+}
+
+// refreshAll refreshes persisted items older than a certain age
+func refreshAll(opts refreshOptions, paths *path.Paths) error {
+	wowItems, err := wowitem.New(paths.Items)
 	if err != nil {
-		return fmt.Errorf("failed to save item persistence: %w", err)
+		return err
 	}
 
-	fmt.Printf("Refreshed %d of %d stale items\n", refreshCount, needsRefresh)
+	ids := staleItems(wowItems, opts.maxAge, opts.filters())
+	needsRefresh := len(ids)
+	ids = ids[:min(opts.maxRefresh, len(ids))]
+
+	flush := func(r *refreshResult) error {
+		return r.flush(paths, wowItems, opts.regenerate)
+	}
+	r, errRefresh := refreshItems(wowItems, ids, opts.fetch, opts.workers, opts.checkpoint, flush)
+
+	if wowItems.Dirty() {
+		err = wowItems.Save()
+		if err != nil {
+			return errors.Join(errRefresh, fmt.Errorf("failed to save item persistence: %w", err))
+		}
+	}
+
+	fmt.Printf("Refreshed %d of %d stale items\n", r.refreshed, needsRefresh)
+
+	// Everything refreshed is saved now, even if a checkpoint failed, so log all of it
+	err = r.report(paths, wowItems, opts.regenerate)
+
+	return errors.Join(errRefresh, err)
+}
+
+// refreshAppearances fetches appearance sets that are not yet persisted
+func refreshAppearances(paths *path.Paths) error {
+	as, err := appearanceset.New(paths.Appearances)
+	if err != nil {
+		return err
+	}
+
+	fetched, errFetch := as.LoadMissingFromWeb()
+
+	// Keep whatever was fetched, even if a later set failed
+	if as.Dirty() {
+		err = as.Save()
+		if err != nil {
+			return fmt.Errorf("failed to save appearances persist: %w", err)
+		}
+	}
+
+	if errFetch != nil {
+		return fmt.Errorf("failed to refresh appearance sets: %w", errFetch)
+	}
+
+	fmt.Printf("Fetched %d new appearance sets, %d persisted\n", fetched, as.Len())
 
 	return nil
 }
@@ -89,24 +294,62 @@ func runRefresh(args []string, paths *path.Paths) error {
 	flags := flag.NewFlagSet("refresh", flag.ExitOnError)
 
 	maxRefresh := flags.Int("max-refresh", 1000, "Maximum number of items to refresh")
+	maxAge := flags.Duration("max-age", 7*24*time.Hour, "Refresh items last updated longer ago than this")
+	class := flags.String("class", "", "Only refresh items of this class")
+	where := flags.String("where", "", "Only refresh items matching this query expression")
+	workers := flags.Int("workers", 4, "Number of items to fetch at once")
+	checkpoint := flags.Int("checkpoint", 100, "Save after this many items are refreshed, 0 to save only at the end")
+	regenerate := flags.Bool("regenerate", false, "Regenerate the wowMerchant price cache if any prices changed")
+	includeSynthetic := flags.Bool("include-synthetic", false, "Also refresh synthetic items, replacing any the web API now has")
 	itemID := flags.Int64("id", -1, "Item ID to look up")
 
 	if err := flags.Parse(args); err != nil {
 		return err
 	}
 
+	if *maxRefresh < 0 {
+		return fmt.Errorf("max-refresh must be at least 0 got %d", *maxRefresh)
+	}
+
+	opts := refreshOptions{
+		maxRefresh: *maxRefresh,
+		regenerate: *regenerate,
+		maxAge:     *maxAge,
+		workers:    *workers,
+		checkpoint: *checkpoint,
+		fetch:      wowitem.Fetch,
+
+		includeSynthetic: *includeSynthetic,
+	}
+
+	if *class != "" {
+		opts.predicates = append(opts.predicates, query.ItemClass(*class))
+	}
+
+	if *where != "" {
+		predicate, err := query.Parse(*where)
+		if err != nil {
+			return fmt.Errorf("invalid -where: %w", err)
+		}
+		opts.predicates = append(opts.predicates, predicate)
+	}
+
 	err := wowapi.Init(paths.Secret)
 	if err != nil {
 		return err
 	}
 
+	if flags.Arg(0) == "appearances" {
+		return refreshAppearances(paths)
+	}
+
 	if *itemID == -1 {
-		err = refreshAll(*maxRefresh, paths)
+		err = refreshAll(opts, paths)
 		if err != nil {
 			return err
 		}
 	} else {
-		err = refreshItem(*itemID, paths)
+		err = refreshItem(*itemID, *regenerate, *includeSynthetic, paths)
 		if err != nil {
 			return err
 		}
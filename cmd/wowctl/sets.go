@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/erikbryant/wow/internal/appearanceset"
+	"github.com/erikbryant/wow/internal/path"
+	"github.com/erikbryant/wow/internal/userconfig"
+	"github.com/erikbryant/wow/internal/wowapi"
+)
+
+// printSets writes one line per appearance set, most complete first
+func printSets(w io.Writer, completions []appearanceset.Completion, minPercent float64, includeComplete bool) {
+	fmt.Fprintf(w, "%6s  %6s  %7s  %s\n", "Set", "Pct", "Owned", "Name")
+
+	for _, c := range completions {
+		if c.Percent() < minPercent {
+			continue
+		}
+		if c.Missing() == 0 && !includeComplete {
+			continue
+		}
+		fmt.Fprintf(w, "%6d  %5.1f%%  %3d/%-3d  %s\n", c.SetID, c.Percent(), c.Owned, c.Total, c.Name)
+	}
+}
+
+func runSets(args []string, paths *path.Paths) error {
+	flags := flag.NewFlagSet("sets", flag.ExitOnError)
+
+	minPercent := flags.Float64("min-percent", 0, "Only show sets at least this percent complete")
+	includeComplete := flags.Bool("include-complete", false, "Also show sets that are already complete")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	err := wowapi.Init(paths.Secret)
+	if err != nil {
+		return err
+	}
+
+	as, err := appearanceset.New(paths.Appearances)
+	if err != nil {
+		return err
+	}
+
+	appearances, err := userconfig.NewAppearances()
+	if err != nil {
+		return err
+	}
+
+	printSets(os.Stdout, as.Completions(appearances.Owned), *minPercent, *includeComplete)
+
+	return nil
+}
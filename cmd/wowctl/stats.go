@@ -0,0 +1,157 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/erikbryant/wow/internal/common"
+	"github.com/erikbryant/wow/internal/path"
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+// ageBucket is one bar of the age histogram
+type ageBucket struct {
+	label  string
+	maxAge time.Duration
+}
+
+var ageBuckets = []ageBucket{
+	{"< 1 day", 24 * time.Hour},
+	{"< 1 week", 7 * 24 * time.Hour},
+	{"< 30 days", 30 * 24 * time.Hour},
+	{"< 90 days", 90 * 24 * time.Hour},
+	{"< 1 year", 365 * 24 * time.Hour},
+	{">= 1 year", 0},
+}
+
+// itemStats summarizes the item cache
+type itemStats struct {
+	total       int
+	byClass     map[string]int
+	bySubclass  map[string]int
+	byQuality   map[string]int
+	synthetic   int
+	noSellPrice int
+	byAge       []int
+	topPrices   []wowitem.Item
+}
+
+// collectStats summarizes items, as of now
+func collectStats(items []wowitem.Item, now time.Time, topN int) itemStats {
+	s := itemStats{
+		total:      len(items),
+		byClass:    map[string]int{},
+		bySubclass: map[string]int{},
+		byQuality:  map[string]int{},
+		byAge:      make([]int, len(ageBuckets)),
+	}
+
+	for _, item := range items {
+		s.byClass[item.ItemClassName()]++
+		s.bySubclass[item.ItemClassName()+" / "+item.ItemSubclassName()]++
+		s.byQuality[item.Quality()]++
+
+		if item.Synthetic() {
+			s.synthetic++
+		}
+		if item.SellPriceAdvertised() == 0 {
+			s.noSellPrice++
+		}
+
+		age := now.Sub(item.Updated())
+		for i, bucket := range ageBuckets {
+			if bucket.maxAge == 0 || age < bucket.maxAge {
+				s.byAge[i]++
+				break
+			}
+		}
+	}
+
+	s.topPrices = slices.Clone(items)
+	slices.SortFunc(s.topPrices, func(a, b wowitem.Item) int {
+		return cmp.Or(
+			cmp.Compare(b.SellPriceAdvertised(), a.SellPriceAdvertised()),
+			cmp.Compare(a.ID(), b.ID()),
+		)
+	})
+	s.topPrices = s.topPrices[:min(topN, len(s.topPrices))]
+
+	return s
+}
+
+// printCounts writes counts, largest first
+func printCounts(w io.Writer, label string, counts map[string]int) {
+	fmt.Fprintf(w, "\n%s:\n", label)
+
+	keys := slices.Collect(maps.Keys(counts))
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+
+	for _, key := range keys {
+		if key == "" {
+			fmt.Fprintf(w, "  %-40s %7d\n", "(none)", counts[key])
+			continue
+		}
+		fmt.Fprintf(w, "  %-40s %7d\n", key, counts[key])
+	}
+}
+
+// printStats writes the summary
+func printStats(w io.Writer, s itemStats) {
+	fmt.Fprintf(w, "Items:             %7d\n", s.total)
+	fmt.Fprintf(w, "Synthetic items:   %7d\n", s.synthetic)
+	fmt.Fprintf(w, "No sell price:     %7d\n", s.noSellPrice)
+
+	printCounts(w, "By class", s.byClass)
+	printCounts(w, "By class / subclass", s.bySubclass)
+	printCounts(w, "By quality", s.byQuality)
+
+	fmt.Fprintf(w, "\nBy age (last updated):\n")
+	for i, bucket := range ageBuckets {
+		fmt.Fprintf(w, "  %-40s %7d\n", bucket.label, s.byAge[i])
+	}
+
+	fmt.Fprintf(w, "\nLargest sell prices:\n")
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, item := range s.topPrices {
+		fmt.Fprintf(writer, "  %d\t%s\t%s\n", item.ID(), common.Gold(item.SellPriceAdvertised()), item.Name())
+	}
+	writer.Flush()
+}
+
+func runStats(args []string, paths *path.Paths) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+
+	top := flags.Int("top", 10, "Number of largest sell prices to show")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	wowItems, err := wowitem.New(paths.Items)
+	if err != nil {
+		return err
+	}
+	loadTime := time.Since(start)
+
+	info, err := os.Stat(wowItems.Path())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("File:              %s\n", wowItems.Path())
+	fmt.Printf("Size on disk:      %7.1f MiB\n", float64(info.Size())/(1024*1024))
+	fmt.Printf("Load time:         %v\n", loadTime.Round(time.Millisecond))
+	printStats(os.Stdout, collectStats(wowItems.Values(), time.Now(), *top))
+
+	return nil
+}
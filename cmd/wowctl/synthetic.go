@@ -1,9 +1,13 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
+	"path/filepath"
+	"text/tabwriter"
 
 	"github.com/erikbryant/wow/internal/appearanceset"
 	"github.com/erikbryant/wow/internal/common"
@@ -15,138 +19,176 @@ import (
 	"github.com/erikbryant/wow/internal/wowitem"
 )
 
-type syntheticOption func(*syntheticitem.Item)
+// syntheticItems converts synthetic item specs, read from source, to items
+func syntheticItems(specs []syntheticitem.Spec, source string) ([]wowitem.Item, error) {
+	items := []wowitem.Item{}
 
-func commodity(item *syntheticitem.Item) {
-	item.SetStackable(true)
+	for _, s := range specs {
+		item, err := wowitem.NewItem(s.Item().SetSource(source).Map())
+		if err != nil {
+			return nil, fmt.Errorf("synthetic item %d: %w", s.ID, err)
+		}
+		items = append(items, *item)
+	}
+
+	return items, nil
 }
 
-func itemClassName(name string) syntheticOption {
-	return func(item *syntheticitem.Item) {
-		item.SetItemClassName(name)
+// synthetics returns the synthetic items we have created.
+func synthetics(paths *path.Paths) ([]wowitem.Item, error) {
+	specs, err := syntheticitem.Load(paths.Synthetics)
+	if err != nil {
+		return nil, err
 	}
+
+	return syntheticItems(specs, filepath.Base(paths.Synthetics))
 }
 
-func price(g, s, c int64) syntheticOption {
-	return func(item *syntheticitem.Item) {
-		item.SetPreviewPrice(common.Coppers(g, s, c))
-	}
+// syntheticStatus is what the web API says about a synthetic item
+type syntheticStatus struct {
+	synthetic wowitem.Item
+	real      *wowitem.Item // nil unless the web API now has the item
+	err       error         // set if the web API could not be asked
+}
+
+// priceDiffers returns true if the web API has the item at a different sell price
+func (s syntheticStatus) priceDiffers() bool {
+	return s.real != nil && s.real.SellPriceAdvertised() != s.synthetic.SellPriceAdvertised()
 }
 
-func level(l int64) syntheticOption {
-	return func(item *syntheticitem.Item) {
-		item.SetItemLevel(l)
+// checkSynthetics asks the web API for each synthetic item
+func checkSynthetics(s []wowitem.Item, fetch func(int64) (wowitem.Item, error)) []syntheticStatus {
+	statuses := []syntheticStatus{}
+
+	for _, item := range s {
+		status := syntheticStatus{synthetic: item}
+
+		apiItem, err := fetch(item.ID())
+		switch {
+		case err == nil:
+			status.real = &apiItem
+		case !errors.Is(err, wowapi.ErrNotFound):
+			status.err = err
+		}
+
+		statuses = append(statuses, status)
 	}
+
+	return statuses
 }
 
-// newItem returns a populated non-commodity Item.
-func newItem(id int64, name string, options ...syntheticOption) wowitem.Item {
-	item := syntheticitem.New(id, name)
+// syntheticValidate returns an error if the web API has any of the synthetic items
+func syntheticValidate(s []wowitem.Item, paths *path.Paths) error {
+	err := wowapi.Init(paths.Secret)
+	if err != nil {
+		return err
+	}
 
-	for _, option := range options {
-		option(item)
+	errs := []error{}
+	for _, status := range checkSynthetics(s, wowitem.Fetch) {
+		switch {
+		case status.err != nil:
+			errs = append(errs, fmt.Errorf("could not check synthetic item %d: %w", status.synthetic.ID(), status.err))
+		case status.real != nil:
+			errs = append(errs, fmt.Errorf("synthetic item with id %d shadows web API", status.synthetic.ID()))
+		}
 	}
 
-	return *wowitem.NewItem(item.Map())
+	return errors.Join(errs...)
 }
 
-// synthetics returns the synthetic items we have created.
-func synthetics() []wowitem.Item {
-	return []wowitem.Item{
-
-		// Items WITHOUT vendor prices.
-
-		newItem(123865, "Relic of Ursol"),
-		newItem(123868, "Relic of Shakama"),
-		newItem(123869, "Relic of Elune"),
-		newItem(147455, "Water Stone"),
-		newItem(203932, "Sentient Book"),
-		newItem(217959, "Incomplete Painting"),
-		newItem(225218, "Echoing Fragment: Hallowfall"),
-		newItem(225219, "Echoing Fragment: The Ringing Deeps"),
-		newItem(225236, "Echoing Fragment: Isle of Dorn"),
-		newItem(225237, "Echoing Fragment: Azj-Kahet"),
-		newItem(268944, "Souvenir Halazzi Idol"),
-		newItem(268945, "Souvenir Nalorakk Mask"),
-		newItem(268946, "Souvenir Jan'alai Key Chain"),
-		newItem(268947, "Souvenir Akil'zon Shine Paper Weight"),
-		newItem(268948, "Fine Antique Silvermoon Drapes"),
-		newItem(268949, "Single Earthen Salt Shaker"),
-		newItem(275670, "Bill of Lading"),
-
-		// Items WITH vendor prices.
-
-		newItem(217958, "Used Socks", level(1), price(0, 0, 1)),
-		newItem(226001, "Pure Gold Stein", level(23), price(200, 0, 0)),
-		newItem(226002, "Expensive-Looking Find", level(23), price(200, 0, 0)),
-		newItem(226004, "Olden Text", level(23), price(200, 0, 0)),
-		newItem(226005, "Ancient Tool", level(23), price(200, 0, 0)),
-
-		// Items WITHOUT vendor prices and are Commodities.
-
-		newItem(178149, "Centurion Anima Core", commodity),
-		newItem(225784, "Potion of Polymorphic Translation: Nerubian", commodity),
-
-		// Items WITH vendor prices and are Commodities.
-
-		newItem(23704, "Eversong Port", level(1), price(0, 0, 75), commodity, itemClassName("Consumable")),
-		newItem(43557, "Poisonous Ivy Berries", level(20), price(0, 0, 25), commodity),
-		newItem(54629, "Prickly Thorn", level(1), price(0, 0, 43), commodity),
-		newItem(60390, "Reticulated Tissue", level(1), price(0, 19, 73), commodity),
-		newItem(60405, "Stubby Bear Tail", level(1), price(0, 22, 22), commodity),
-		newItem(60406, "Blood-Caked Incisors", level(1), price(0, 37, 27), commodity),
-		newItem(62770, "Infested Feather", level(1), price(0, 0, 3), commodity),
-		newItem(201420, "Gnolan's House Special", level(21), price(0, 18, 75), commodity, itemClassName("Consumable")),
-		newItem(201421, "Tuskarr Jerky", level(1), price(0, 12, 50), commodity, itemClassName("Consumable")),
-		newItem(204836, "Insect Treasure", level(21), price(0, 0, 50), commodity),
-		newItem(204837, "Rotting Fruit", level(21), price(0, 0, 50), commodity),
-		newItem(204838, "Discarded Toy", level(21), price(0, 0, 50), commodity),
-		newItem(204840, "Bottled Pheromones", level(21), price(0, 0, 50), commodity),
-		newItem(204842, "Red Sparklepretty", level(21), price(0, 0, 50), commodity),
-		newItem(212531, "Ruined Candle", level(1), price(50, 0, 0), commodity),
-		newItem(212533, "Ear Worm", level(1), price(50, 0, 0), commodity),
-		newItem(212534, "Wax Carving of a Candle", level(1), price(50, 0, 0), commodity),
-		newItem(213234, "Rusty Ritual Knife", level(23), price(20, 0, 0), commodity),
-		newItem(213235, "Summoning Circle Chalk", level(23), price(10, 0, 0), commodity),
-		newItem(213237, "Harbinger Idol", level(23), price(20, 0, 0), commodity),
-		newItem(213238, "Broken Shadow Beast Binding", level(23), price(10, 0, 0), commodity),
-		newItem(213240, "Decorated Truffle", level(23), price(30, 0, 0), commodity),
-		newItem(213242, "Adventures of Libarbie and Lichen", level(23), price(30, 0, 0), commodity),
-		newItem(213245, "Gnawed Binding", level(23), price(30, 0, 0), commodity),
-		newItem(213247, "Razor-Sharp Bones", level(23), price(10, 0, 0), commodity),
-		newItem(213250, "Cracked Gem", level(23), price(10, 0, 0), commodity),
-		newItem(213251, "Cinderbee Wax Jar", level(23), price(20, 0, 0), commodity),
-		newItem(213252, "Stolen Earthen Contraption", level(23), price(10, 0, 0), commodity),
-		newItem(213253, "Gilded Candle", level(23), price(20, 0, 0), commodity),
-		newItem(213254, "Big Gold Nugget", level(23), price(10, 0, 0), commodity),
-		newItem(213255, "Wax Canary", level(23), price(20, 0, 0), commodity),
-		newItem(213256, "Wax Spoon", level(23), price(30, 0, 0), commodity),
-		newItem(213257, "Wax Shovel", level(23), price(30, 0, 0), commodity),
-		newItem(213258, "Odorant Oddity", level(23), price(10, 0, 0), commodity),
-		newItem(213259, "Silk Doll", level(23), price(20, 0, 0), commodity),
-		newItem(213261, "Niffen Smell Pouch", level(23), price(30, 0, 0), commodity),
-		newItem(213262, "Stained Glass Fragment", level(23), price(10, 0, 0), commodity),
-		newItem(213263, "Poison Needle", level(23), price(30, 0, 0), commodity),
-		newItem(213266, "Twitching Snack", level(23), price(10, 0, 0), commodity),
-		newItem(213267, "Idol of Ansurek", level(23), price(30, 0, 0), commodity),
-		newItem(222906, "Plump Snapcrab", level(1), price(0, 0, 1), commodity),
-		newItem(224153, "Nibbled Shroomcap", level(23), price(10, 0, 0), commodity),
-		newItem(224154, "Mushrock", level(23), price(20, 0, 0), commodity),
-		newItem(224155, "Peeled Fungal Scale", level(23), price(20, 0, 0), commodity),
-		newItem(226003, "Snake Oil", level(23), price(200, 0, 0), commodity),
+// printSyntheticCheck writes the status of each synthetic item and a summary
+func printSyntheticCheck(w io.Writer, statuses []syntheticStatus) {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "Status\tID\tSynthetic Price\tReal Price\tNote\tName")
+	fmt.Fprintln(writer, "------\t--\t---------------\t----------\t----\t----")
+
+	missing, found, differ, failed := 0, 0, 0, 0
+	for _, s := range statuses {
+		status, realPrice, note := "missing", "", ""
+		switch {
+		case s.err != nil:
+			status, note = "error", s.err.Error()
+			failed++
+		case s.real != nil:
+			status, realPrice = "real", common.Gold(s.real.SellPriceAdvertised())
+			found++
+			if s.priceDiffers() {
+				note = "price differs"
+				differ++
+			}
+		default:
+			missing++
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\t%s\n", status, s.synthetic.ID(), common.Gold(s.synthetic.SellPriceAdvertised()), realPrice, note, s.synthetic.Name())
 	}
+
+	writer.Flush()
+
+	fmt.Fprintf(w, "\n%d still missing from the web API, %d now real (%d at a different price), %d could not be checked\n", missing, found, differ, failed)
 }
 
-func syntheticValidate(s []wowitem.Item, paths *path.Paths) error {
-	err := wowapi.Init(paths.Secret)
+// syntheticCheck reports which synthetic items the web API now has, optionally
+// replacing them with the real data
+func syntheticCheck(args []string, paths *path.Paths) error {
+	flags := flag.NewFlagSet("synthetic check", flag.ExitOnError)
+
+	replace := flags.Bool("replace", false, "Replace synthetic items the web API now has with the real data")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	specs, err := syntheticitem.Load(paths.Synthetics)
 	if err != nil {
 		return err
 	}
 
-	for _, item := range s {
-		_, err := wowapi.Item(strconv.FormatInt(item.ID(), 10))
-		if err == nil {
-			return fmt.Errorf("synthetic item with id %d shadows web API", item.ID())
+	err = wowapi.Init(paths.Secret)
+	if err != nil {
+		return err
+	}
+
+	items, err := syntheticItems(specs, filepath.Base(paths.Synthetics))
+	if err != nil {
+		return err
+	}
+
+	statuses := checkSynthetics(items, wowitem.Fetch)
+	printSyntheticCheck(os.Stdout, statuses)
+
+	if *replace {
+		realItems := []wowitem.Item{}
+		for _, s := range statuses {
+			if s.real == nil {
+				continue
+			}
+			realItems = append(realItems, *s.real)
+			specs, err = syntheticitem.Remove(specs, s.real.ID())
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(realItems) > 0 {
+			err = syntheticitem.Save(paths.Synthetics, specs)
+			if err != nil {
+				return err
+			}
+
+			err = syntheticStore(paths, realItems, nil)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Replaced %d synthetic items with web API data\n", len(realItems))
+		}
+	}
+
+	for _, s := range statuses {
+		if s.err != nil {
+			return fmt.Errorf("could not check every synthetic item")
 		}
 	}
 
@@ -160,7 +202,11 @@ func syntheticList(paths *path.Paths) error {
 		return err
 	}
 
-	s := synthetics()
+	s, err := synthetics(paths)
+	if err != nil {
+		return err
+	}
+
 	query.Sort(s, query.ByID)
 	output.Table(os.Stdout, s, as)
 
@@ -169,40 +215,153 @@ func syntheticList(paths *path.Paths) error {
 
 // syntheticPopulate adds each of the synthetic items to the items persist
 func syntheticPopulate(paths *path.Paths) error {
-	s := synthetics()
+	s, err := synthetics(paths)
+	if err != nil {
+		return err
+	}
+
+	err = syntheticValidate(s, paths)
+	if err != nil {
+		return err
+	}
 
-	err := syntheticValidate(s, paths)
+	err = syntheticStore(paths, s, nil)
 	if err != nil {
 		return err
 	}
 
+	fmt.Fprintf(os.Stdout, "\nPopulated %d items:\n\n", len(s))
+	err = syntheticList(paths)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// syntheticStore sets and deletes items in the items persist and saves it
+func syntheticStore(paths *path.Paths, set []wowitem.Item, deleteIDs []int64) error {
 	wowItems, err := wowitem.New(paths.Items)
 	if err != nil {
 		return err
 	}
 
-	for _, item := range s {
+	for _, item := range set {
 		wowItems.Set(item.ID(), item)
 	}
+	for _, id := range deleteIDs {
+		wowItems.Delete(id)
+	}
 
 	err = wowItems.Save()
 	if err != nil {
 		return fmt.Errorf("failed to save item persistence: %w", err)
 	}
 
-	fmt.Fprintf(os.Stdout, "\nPopulated %d items:\n\n", len(s))
-	err = syntheticList(paths)
+	return nil
+}
+
+// syntheticAdd adds an item to the synthetic items data file and the items persist
+func syntheticAdd(args []string, paths *path.Paths) error {
+	flags := flag.NewFlagSet("synthetic add", flag.ExitOnError)
+
+	id := flags.Int64("id", 0, "Item ID")
+	name := flags.String("name", "", "Item name")
+	price := flags.Int64("price", 0, "Vendor sell price, in coppers")
+	level := flags.Int64("level", 1, "Item level")
+	commodity := flags.Bool("commodity", false, "Item is a stackable commodity")
+	class := flags.String("class", "", "Item class (default Miscellaneous)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *id == 0 || *name == "" {
+		usage()
+		return fmt.Errorf("synthetic add requires -id and -name")
+	}
+
+	spec := syntheticitem.Spec{ID: *id, Name: *name, Level: *level, Price: *price, Commodity: *commodity, Class: *class}
+
+	specs, err := syntheticitem.Load(paths.Synthetics)
+	if err != nil {
+		return err
+	}
+
+	specs, err = syntheticitem.Add(specs, spec)
+	if err != nil {
+		return err
+	}
+
+	item, err := syntheticItems([]syntheticitem.Spec{spec}, filepath.Base(paths.Synthetics))
+	if err != nil {
+		return err
+	}
+
+	err = syntheticValidate(item, paths)
+	if err != nil {
+		return err
+	}
+
+	err = syntheticitem.Save(paths.Synthetics, specs)
 	if err != nil {
 		return err
 	}
 
+	err = syntheticStore(paths, item, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Added synthetic item %d %s\n", *id, *name)
+
+	return nil
+}
+
+// syntheticRemove removes an item from the synthetic items data file and the items persist
+func syntheticRemove(args []string, paths *path.Paths) error {
+	flags := flag.NewFlagSet("synthetic remove", flag.ExitOnError)
+
+	id := flags.Int64("id", 0, "Item ID")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *id == 0 {
+		usage()
+		return fmt.Errorf("synthetic remove requires -id")
+	}
+
+	specs, err := syntheticitem.Load(paths.Synthetics)
+	if err != nil {
+		return err
+	}
+
+	specs, err = syntheticitem.Remove(specs, *id)
+	if err != nil {
+		return err
+	}
+
+	err = syntheticitem.Save(paths.Synthetics, specs)
+	if err != nil {
+		return err
+	}
+
+	err = syntheticStore(paths, nil, []int64{*id})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed synthetic item %d\n", *id)
+
 	return nil
 }
 
 func runSynthetic(args []string, paths *path.Paths) error {
-	if len(args) != 1 {
+	if len(args) < 1 {
 		usage()
-		return fmt.Errorf("synthetic requires one argument")
+		return fmt.Errorf("synthetic requires a command")
 	}
 
 	cmd := args[0]
@@ -212,6 +371,12 @@ func runSynthetic(args []string, paths *path.Paths) error {
 		return syntheticList(paths)
 	case "populate":
 		return syntheticPopulate(paths)
+	case "add":
+		return syntheticAdd(args[1:], paths)
+	case "remove":
+		return syntheticRemove(args[1:], paths)
+	case "check":
+		return syntheticCheck(args[1:], paths)
 	default:
 		usage()
 		return fmt.Errorf("unknown command: %s", cmd)
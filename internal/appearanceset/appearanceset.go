@@ -1,28 +1,68 @@
 package appearanceset
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/erikbryant/wow/internal/persist"
 	"github.com/erikbryant/wow/internal/wowapi"
 )
 
+// Set holds a single appearance set
+type Set struct {
+	// WARNING: Changing this struct invalidates the persistence.
+	// These members have to be public to write to a gob file.
+	Name        string
+	Appearances []int64
+	Fetched     time.Time
+}
+
+// Completion reports how much of an appearance set I own
+type Completion struct {
+	SetID int64
+	Name  string
+	Owned int
+	Total int
+}
+
+// Missing returns the number of appearances still needed to complete the set
+func (c Completion) Missing() int {
+	return c.Total - c.Owned
+}
+
+// Percent returns how complete the set is, 0-100
+func (c Completion) Percent() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return 100 * float64(c.Owned) / float64(c.Total)
+}
+
 type Persistence struct {
-	*persist.Persistence[int64, bool]
+	*persist.Persistence[int64, Set]
+
+	// setsByAppearance maps appearance ID to the IDs of the sets it is in
+	mu               sync.RWMutex
+	setsByAppearance map[int64][]int64
 }
 
 // NewEmpty creates a new Persistence with no items in it.
 func NewEmpty(persistencePath string) *Persistence {
 	return &Persistence{
-		Persistence: persist.New[int64, bool](persistencePath + ".new"),
+		Persistence:      persist.New[int64, Set](persistencePath + ".new"),
+		setsByAppearance: map[int64][]int64{},
 	}
 }
 
 // New creates a new Persistence, populated with data from its persistence store.
 func New(persistencePath string) (*Persistence, error) {
 	as := &Persistence{
-		Persistence: persist.New[int64, bool](persistencePath),
+		Persistence: persist.New[int64, Set](persistencePath),
 	}
 
 	if err := as.Load(); err != nil {
@@ -32,40 +72,219 @@ func New(persistencePath string) (*Persistence, error) {
 	return as, nil
 }
 
-func (as *Persistence) LoadFromWeb() error {
-	appearanceSetsIDs, err := wowapi.ItemAppearanceSetsIndexIDs()
+// index rebuilds the appearance to set lookup from the persisted sets
+func (as *Persistence) index() {
+	setsByAppearance := map[int64][]int64{}
+
+	for _, setID := range as.Keys() {
+		set, _ := as.Get(setID)
+		for _, appearanceID := range set.Appearances {
+			setsByAppearance[appearanceID] = append(setsByAppearance[appearanceID], setID)
+		}
+	}
+
+	as.mu.Lock()
+	as.setsByAppearance = setsByAppearance
+	as.mu.Unlock()
+}
+
+// Load replaces the current data with the contents of the persistence file.
+func (as *Persistence) Load() error {
+	err := as.Persistence.Load()
 	if err != nil {
 		return err
 	}
 
-	total := len(appearanceSetsIDs)
-	count := 1
+	as.index()
+
+	return nil
+}
+
+// unindex removes a set from the appearance to set lookup. Caller holds as.mu.
+func (as *Persistence) unindex(setID int64) {
+	old, ok := as.Get(setID)
+	if !ok {
+		return
+	}
+
+	for _, appearanceID := range old.Appearances {
+		setIDs := slices.DeleteFunc(as.setsByAppearance[appearanceID], func(id int64) bool { return id == setID })
+		if len(setIDs) == 0 {
+			delete(as.setsByAppearance, appearanceID)
+			continue
+		}
+		as.setsByAppearance[appearanceID] = setIDs
+	}
+}
+
+// Set stores an appearance set and marks the persistence dirty.
+func (as *Persistence) Set(setID int64, set Set) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	as.unindex(setID)
+	as.Persistence.Set(setID, set)
+
+	if as.setsByAppearance == nil {
+		as.setsByAppearance = map[int64][]int64{}
+	}
+	for _, appearanceID := range set.Appearances {
+		as.setsByAppearance[appearanceID] = append(as.setsByAppearance[appearanceID], setID)
+	}
+}
+
+// Delete removes an appearance set, if present, and marks the persistence dirty.
+func (as *Persistence) Delete(setID int64) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	as.unindex(setID)
+	as.Persistence.Delete(setID)
+}
+
+// Keys returns all set IDs, sorted.
+func (as *Persistence) Keys() []int64 {
+	keys := as.Persistence.Keys()
+	slices.Sort(keys)
+	return keys
+}
+
+// fetch retrieves the listed sets from the web and stores them. It returns the number fetched.
+func (as *Persistence) fetch(setIDs map[int64]string, getAppearances func(int64) ([]int64, error)) (int, error) {
+	fetched := 0
 
-	for setID, setName := range appearanceSetsIDs {
-		fmt.Fprintf(os.Stderr, "Loading appearance set %4d/%4d: %5d  %s\n", count, total, setID, setName)
-		count++
+	for _, setID := range slices.Sorted(maps.Keys(setIDs)) {
+		setName := setIDs[setID]
+		fmt.Fprintf(os.Stderr, "Loading appearance set %4d/%4d: %5d  %s\n", fetched+1, len(setIDs), setID, setName)
 
-		appearanceIDs, err := wowapi.ItemAppearanceSetIDs(setID)
+		appearanceIDs, err := getAppearances(setID)
 		if err != nil {
-			return err
+			return fetched, err
 		}
 
-		for _, appearanceID := range appearanceIDs {
-			as.Set(appearanceID, true)
+		as.Set(setID, Set{Name: setName, Appearances: appearanceIDs, Fetched: time.Now()})
+		fetched++
+	}
+
+	return fetched, nil
+}
+
+// missing returns the sets in the index that are not yet persisted
+func (as *Persistence) missing(index map[int64]string) map[int64]string {
+	setIDs := map[int64]string{}
+
+	for setID, setName := range index {
+		if _, ok := as.Get(setID); !ok {
+			setIDs[setID] = setName
 		}
 	}
 
-	return nil
+	return setIDs
+}
+
+// LoadFromWeb fetches every appearance set from the web.
+func (as *Persistence) LoadFromWeb() error {
+	appearanceSetsIDs, err := wowapi.ItemAppearanceSetsIndexIDs()
+	if err != nil {
+		return err
+	}
+
+	_, err = as.fetch(appearanceSetsIDs, wowapi.ItemAppearanceSetIDs)
+	return err
+}
+
+// LoadMissingFromWeb fetches only the appearance sets that are not yet persisted.
+// It returns the number of sets fetched.
+func (as *Persistence) LoadMissingFromWeb() (int, error) {
+	appearanceSetsIDs, err := wowapi.ItemAppearanceSetsIndexIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	return as.fetch(as.missing(appearanceSetsIDs), wowapi.ItemAppearanceSetIDs)
+}
+
+// SetsContaining returns the IDs of the sets that contain any of these appearance IDs.
+func (as *Persistence) SetsContaining(appearanceIDs []int64) []int64 {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	setIDs := []int64{}
+	for _, appearanceID := range appearanceIDs {
+		setIDs = append(setIDs, as.setsByAppearance[appearanceID]...)
+	}
+
+	slices.Sort(setIDs)
+	return slices.Compact(setIDs)
 }
 
 // Contains returns true if any of these appearance IDs are in an appearance set.
 func (as *Persistence) Contains(appearanceIDs []int64) bool {
-	for _, appearanceID := range appearanceIDs {
-		inSet, ok := as.Get(appearanceID)
-		if ok && inSet {
-			return true
+	return len(as.SetsContaining(appearanceIDs)) > 0
+}
+
+// LenAppearances returns the number of distinct appearances that are in some set.
+func (as *Persistence) LenAppearances() int {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	return len(as.setsByAppearance)
+}
+
+// Completion returns how many of the set's appearances I own.
+func (as *Persistence) Completion(setID int64, owned func(appearanceID int64) bool) Completion {
+	set, _ := as.Get(setID)
+
+	c := Completion{
+		SetID: setID,
+		Name:  set.Name,
+		Total: len(set.Appearances),
+	}
+
+	for _, appearanceID := range set.Appearances {
+		if owned(appearanceID) {
+			c.Owned++
+		}
+	}
+
+	return c
+}
+
+// Completions returns the completion of every set, most complete first.
+func (as *Persistence) Completions(owned func(appearanceID int64) bool) []Completion {
+	completions := []Completion{}
+
+	for _, setID := range as.Keys() {
+		completions = append(completions, as.Completion(setID, owned))
+	}
+
+	slices.SortStableFunc(completions, func(a, b Completion) int {
+		return cmp.Or(
+			cmp.Compare(b.Percent(), a.Percent()),
+			cmp.Compare(a.Missing(), b.Missing()),
+		)
+	})
+
+	return completions
+}
+
+// ClosestToComplete returns the unfinished set containing any of these
+// appearance IDs that has the fewest appearances left to collect. Ties go to
+// the set that is further along.
+func (as *Persistence) ClosestToComplete(appearanceIDs []int64, owned func(appearanceID int64) bool) (Completion, bool) {
+	var closest Completion
+	found := false
+
+	for _, setID := range as.SetsContaining(appearanceIDs) {
+		c := as.Completion(setID, owned)
+		if c.Missing() == 0 {
+			continue
+		}
+		if !found || c.Missing() < closest.Missing() || (c.Missing() == closest.Missing() && c.Percent() > closest.Percent()) {
+			closest = c
+			found = true
 		}
 	}
 
-	return false
+	return closest, found
 }
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/erikbryant/wow/internal/persist"
 )
@@ -14,7 +16,7 @@ func newTestPersistence(t *testing.T) *Persistence {
 	t.Helper()
 
 	return &Persistence{
-		Persistence: persist.New[int64, bool](filepath.Join(t.TempDir(), "appearances")),
+		Persistence: persist.New[int64, Set](filepath.Join(t.TempDir(), "appearances")),
 	}
 }
 
@@ -42,8 +44,8 @@ func TestNewEmpty(t *testing.T) {
 func TestContains(t *testing.T) {
 	as := newTestPersistence(t)
 
-	as.Set(100, true)
-	as.Set(200, false)
+	as.Set(1, Set{Name: "Set One", Appearances: []int64{100, 101}})
+	as.Set(2, Set{Name: "Set Two"})
 
 	tests := []struct {
 		name string
@@ -52,7 +54,7 @@ func TestContains(t *testing.T) {
 	}{
 		{name: "matching appearance", ids: []int64{100}, want: true},
 		{name: "matching appearance among nonmatches", ids: []int64{1, 2, 100, 3}, want: true},
-		{name: "false value is not in set", ids: []int64{200}, want: false},
+		{name: "set ID is not an appearance", ids: []int64{2}, want: false},
 		{name: "missing appearance", ids: []int64{300}, want: false},
 		{name: "empty list", ids: nil, want: false},
 	}
@@ -69,8 +71,7 @@ func TestContains(t *testing.T) {
 func TestContainsDoesNotModifyPersistence(t *testing.T) {
 	as := newTestPersistence(t)
 
-	as.Set(100, true)
-	as.Set(200, false)
+	as.Set(1, Set{Name: "Set One", Appearances: []int64{100}})
 	before := as.Len()
 
 	if !as.Contains([]int64{999, 100, 200}) {
@@ -82,12 +83,72 @@ func TestContainsDoesNotModifyPersistence(t *testing.T) {
 	}
 }
 
+func TestSetsContaining(t *testing.T) {
+	as := newTestPersistence(t)
+
+	as.Set(2, Set{Name: "Set Two", Appearances: []int64{100, 200}})
+	as.Set(1, Set{Name: "Set One", Appearances: []int64{100, 101}})
+
+	if got, want := as.SetsContaining([]int64{100, 200, 999}), []int64{1, 2}; !slices.Equal(got, want) {
+		t.Fatalf("SetsContaining() = %v, want %v", got, want)
+	}
+	if got, want := as.LenAppearances(), 3; got != want {
+		t.Fatalf("LenAppearances() = %d, want %d", got, want)
+	}
+
+	// Replacing a set drops its old appearances from the index
+	as.Set(2, Set{Name: "Set Two", Appearances: []int64{300}})
+	if got, want := as.SetsContaining([]int64{200, 300}), []int64{2}; !slices.Equal(got, want) {
+		t.Fatalf("SetsContaining() after replace = %v, want %v", got, want)
+	}
+	if got, want := as.SetsContaining([]int64{100}), []int64{1}; !slices.Equal(got, want) {
+		t.Fatalf("SetsContaining(100) after replace = %v, want %v", got, want)
+	}
+
+	as.Delete(1)
+	if as.Contains([]int64{100, 101}) {
+		t.Fatal("Contains() = true after Delete, want false")
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	as := newTestPersistence(t)
+
+	as.Set(1, Set{Name: "Half", Appearances: []int64{10, 11}})
+	as.Set(2, Set{Name: "Done", Appearances: []int64{10}})
+	as.Set(3, Set{Name: "Nearly", Appearances: []int64{10, 12, 13, 14}})
+	as.Set(4, Set{Name: "Empty"})
+
+	owned := func(id int64) bool { return id == 10 || id == 12 || id == 13 }
+
+	c := as.Completion(3, owned)
+	if c.Name != "Nearly" || c.Owned != 3 || c.Total != 4 || c.Missing() != 1 || c.Percent() != 75 {
+		t.Fatalf("Completion(3) = %+v", c)
+	}
+
+	got := []int64{}
+	for _, c := range as.Completions(owned) {
+		got = append(got, c.SetID)
+	}
+	if want := []int64{2, 3, 1, 4}; !slices.Equal(got, want) {
+		t.Fatalf("Completions() order = %v, want %v", got, want)
+	}
+
+	closest, ok := as.ClosestToComplete([]int64{10}, owned)
+	if !ok || closest.SetID != 3 {
+		t.Fatalf("ClosestToComplete(10) = %+v, %v, want set 3", closest, ok)
+	}
+	if _, ok := as.ClosestToComplete([]int64{999}, owned); ok {
+		t.Fatal("ClosestToComplete(999) found a set, want none")
+	}
+}
+
 func TestNewLoadsPersistence(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "appearances")
 
-	stored := persist.New[int64, bool](path)
-	stored.Set(100, true)
-	stored.Set(200, false)
+	stored := persist.New[int64, Set](path)
+	stored.Set(1, Set{Name: "Set One", Appearances: []int64{100}})
+	stored.Set(2, Set{Name: "Set Two", Appearances: []int64{200, 201}})
 	if err := stored.Save(); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
@@ -100,11 +161,11 @@ func TestNewLoadsPersistence(t *testing.T) {
 	if got, want := as.Len(), 2; got != want {
 		t.Fatalf("Len() = %d, want %d", got, want)
 	}
-	if !as.Contains([]int64{100}) {
-		t.Fatal("Contains(100) = false, want true")
+	if !as.Contains([]int64{201}) {
+		t.Fatal("Contains(201) = false, want true")
 	}
-	if as.Contains([]int64{200}) {
-		t.Fatal("Contains(200) = true, want false")
+	if as.Contains([]int64{300}) {
+		t.Fatal("Contains(300) = true, want false")
 	}
 	if as.Dirty() {
 		t.Fatal("loaded persistence is dirty")
@@ -129,7 +190,7 @@ func TestNewMissingPersistence(t *testing.T) {
 func TestNewCorruptPersistence(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "appearances")
 
-	stored := persist.New[int64, bool](path)
+	stored := persist.New[int64, Set](path)
 	if err := stored.Save(); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
@@ -162,3 +223,56 @@ func TestNewPreservesUnderlyingError(t *testing.T) {
 		t.Fatalf("New() error = %v, want errors.Is(..., os.ErrNotExist)", err)
 	}
 }
+
+func TestFetchMissing(t *testing.T) {
+	as := newTestPersistence(t)
+	as.Set(1, Set{Name: "Set One", Appearances: []int64{100}})
+
+	index := map[int64]string{1: "Set One", 2: "Set Two", 3: "Set Three"}
+	requested := []int64{}
+	getAppearances := func(setID int64) ([]int64, error) {
+		requested = append(requested, setID)
+		return []int64{setID * 100}, nil
+	}
+
+	before := time.Now()
+	fetched, err := as.fetch(as.missing(index), getAppearances)
+	if err != nil {
+		t.Fatalf("fetch() error = %v", err)
+	}
+	if fetched != 2 || !slices.Equal(requested, []int64{2, 3}) {
+		t.Fatalf("fetch() = %d, requested %v, want 2, [2 3]", fetched, requested)
+	}
+
+	set, ok := as.Get(3)
+	if !ok || set.Name != "Set Three" || set.Fetched.Before(before) {
+		t.Fatalf("Get(3) = %+v, %v", set, ok)
+	}
+	if !as.Contains([]int64{300}) {
+		t.Fatal("Contains(300) = false, want true")
+	}
+
+	if missing := as.missing(index); len(missing) != 0 {
+		t.Fatalf("missing() = %v after fetch, want none", missing)
+	}
+}
+
+func TestFetchError(t *testing.T) {
+	as := newTestPersistence(t)
+
+	wantErr := errors.New("boom")
+	getAppearances := func(setID int64) ([]int64, error) {
+		if setID == 2 {
+			return nil, wantErr
+		}
+		return []int64{setID}, nil
+	}
+
+	fetched, err := as.fetch(map[int64]string{1: "Set One", 2: "Set Two"}, getAppearances)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("fetch() error = %v, want %v", err, wantErr)
+	}
+	if fetched != 1 || as.Len() != 1 {
+		t.Fatalf("fetch() = %d, Len() = %d, want 1, 1", fetched, as.Len())
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/erikbryant/wow/internal/appearanceset"
 	"github.com/erikbryant/wow/internal/battlepet"
 	"github.com/erikbryant/wow/internal/cooking"
+	"github.com/erikbryant/wow/internal/heirloom"
+	"github.com/erikbryant/wow/internal/mount"
 	"github.com/erikbryant/wow/internal/path"
 	"github.com/erikbryant/wow/internal/shoppingconfig"
 	"github.com/erikbryant/wow/internal/toy"
@@ -23,6 +25,9 @@ type App struct {
 	Appearances    *userconfig.Appearances
 	BattlePets     *battlepet.BattlePet
 	Cooking        *cooking.CookingRecipes
+	Heirlooms      *heirloom.Heirloom
+	ILevels        *wowitem.ILevels
+	Mounts         *mount.Mount
 	ShoppingConfig *shoppingconfig.UserConfig
 	Toys           *toy.Toy
 	WowAPI         *wowapi.Client
@@ -72,16 +77,45 @@ func New(rootPath string) (*App, error) {
 		return nil, err
 	}
 
-	app.ShoppingConfig = shoppingconfig.New(app.WowItem, app.Cooking)
+	app.Heirlooms, err = heirloom.New(app.Paths.HeirloomItems)
+	if err != nil {
+		return nil, err
+	}
 
-	app.Toys, err = toy.New()
+	app.ILevels, err = wowitem.NewILevels(app.Paths.ILevels)
 	if err != nil {
 		return nil, err
 	}
 
+	app.Mounts, err = mount.New()
+	if err != nil {
+		return nil, err
+	}
+
+	app.ShoppingConfig, err = shoppingconfig.New(app.WowItem, app.Cooking)
+	if err != nil {
+		return nil, err
+	}
+
+	app.Toys, err = toy.New(app.Paths.ToyItems)
+	if err != nil {
+		return nil, err
+	}
+
+	complete := 0
+	for _, c := range app.AppearanceSet.Completions(app.Appearances.Owned) {
+		if c.Missing() == 0 {
+			complete++
+		}
+	}
+
 	fmt.Printf("-- #Items persisted        : %d\n", app.WowItem.Len())
-	fmt.Printf("-- #Appearances owned      : %d/%d\n", app.AppearanceSet.Len(), app.Appearances.Len())
+	fmt.Printf("-- #Appearances owned      : %d\n", app.Appearances.Len())
+	fmt.Printf("-- #Appearance sets done   : %d/%d\n", complete, app.AppearanceSet.Len())
 	fmt.Printf("-- #Battlepet species owned: %d/%d\n", app.BattlePets.LenOwned(), app.BattlePets.LenNames())
+	fmt.Printf("-- #Mounts owned           : %d/%d\n", app.Mounts.LenOwned(), app.Mounts.LenIndex())
+	fmt.Printf("-- #Toys owned             : %d/%d\n", app.Toys.LenOwned(), app.Toys.LenIndex())
+	fmt.Printf("-- #Heirlooms owned        : %d/%d\n", app.Heirlooms.LenOwned(), app.Heirlooms.LenIndex())
 
 	return &app, nil
 }
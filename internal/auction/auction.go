@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/erikbryant/web"
 	"github.com/erikbryant/wow/internal/battlepet"
 	"github.com/erikbryant/wow/internal/common"
+	"github.com/erikbryant/wow/internal/diagnostic"
 	"github.com/erikbryant/wow/internal/wowapi"
 )
 
@@ -17,9 +19,64 @@ import (
 // Sample 'auction' response. Some have more or fewer fields.
 // map[buyout:1.1111011e+09 id:3.49632108e+08 item:map[id:142075] quantity:1 time_left:VERY_LONG]
 
+// Sample 'auction' response that accepts bids. Some also have a buyout.
+// map[bid:1.5e+06 id:3.49632109e+08 item:map[id:142075] quantity:1 time_left:SHORT]
+
+// Sample 'auction' response for a crafted item. The bonus list sets its quality, and so its iLevel.
+// map[buyout:4.5e+06 id:5.01784775e+08 item:map[bonus_lists:[12345 12217] context:13 id:238011 modifiers:[map[type:9 value:80]]] quantity:1 time_left:LONG]
+
 // Sample 'auction' response for a pet auction. ItemID 82800 is a 'Pet Cage'. Pet cages have no sell value.
 // map[buyout:9.99e+06 id:5.01784773e+08 item:map[id:82800 modifiers:[map[type:6 value:39130]] pet_breed_id:20 pet_level:1 pet_quality_id:2 pet_species_id:1446] quantity:1 time_left:VERY_LONG]
 
+// TimeLeft is how long an auction has left to run, in the buckets the web API reports
+type TimeLeft int
+
+const (
+	TimeLeftUnknown  TimeLeft = iota
+	TimeLeftShort             // Less than 30 minutes
+	TimeLeftMedium            // 30 minutes to 2 hours
+	TimeLeftLong              // 2 to 12 hours
+	TimeLeftVeryLong          // 12 to 48 hours
+)
+
+var timeLeftNames = map[string]TimeLeft{
+	"SHORT":     TimeLeftShort,
+	"MEDIUM":    TimeLeftMedium,
+	"LONG":      TimeLeftLong,
+	"VERY_LONG": TimeLeftVeryLong,
+}
+
+func (t TimeLeft) String() string {
+	for name, value := range timeLeftNames {
+		if value == t {
+			return name
+		}
+	}
+	return "UNKNOWN"
+}
+
+// Max returns the longest an auction with this much time left can still run
+func (t TimeLeft) Max() time.Duration {
+	switch t {
+	case TimeLeftShort:
+		return 30 * time.Minute
+	case TimeLeftMedium:
+		return 2 * time.Hour
+	case TimeLeftLong:
+		return 12 * time.Hour
+	default:
+		return 48 * time.Hour
+	}
+}
+
+// ModifierType identifies an item modifier
+type ModifierType int64
+
+const (
+	ModifierBattlePetDisplayID ModifierType = 6 // The display ID of a caged pet
+	ModifierTimewalkerLevel    ModifierType = 9 // The player level the item was made or looted at
+)
+
 // PetInfo contains the properties specific to a battle pet
 type PetInfo struct {
 	Level     int64
@@ -33,108 +90,236 @@ type Auction struct {
 	ID       int64
 	ItemID   int64
 	Buyout   int64 // For commodity auctions this stores 'unit_price'
+	Bid      int64 // The current bid, 0 if the auction does not accept bids
 	Quantity int64
+	TimeLeft TimeLeft
 	Pet      PetInfo
+
+	// BonusLists and Modifiers describe the variant of the item for sale (e.g. its crafted quality)
+	BonusLists []int64
+	Modifiers  map[ModifierType]int64
+	Context    int64 // where the item came from (e.g. a raid difficulty or a crafting order)
+}
+
+// ExpiresWithin returns true if the auction is certain to end within d
+func (a Auction) ExpiresWithin(d time.Duration) bool {
+	return a.TimeLeft != TimeLeftUnknown && a.TimeLeft.Max() <= d
 }
 
-func auctionID(msi any) int64 {
-	value, err := web.MsiValue(msi, []string{"id"})
+// HasBonuses returns true if the item for sale has bonuses, so may differ from its base version
+func (a Auction) HasBonuses() bool {
+	return len(a.BonusLists) > 0
+}
+
+// jsonInt64 returns the JSON number at keys
+func jsonInt64(msi any, keys []string) (int64, error) {
+	value, err := web.MsiValue(msi, keys)
 	if err != nil {
-		panic(fmt.Errorf("auction id missing from %v: %w", msi, err))
+		return 0, fmt.Errorf("%s missing: %w", strings.Join(keys, "."), err)
 	}
-	return common.JSONInt64Panic(value)
+	n, err := common.JSONInt64(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", strings.Join(keys, "."), err)
+	}
+	return n, nil
 }
 
-func itemID(msi any) int64 {
-	value, err := web.MsiValue(msi, []string{"item", "id"})
+// optionalInt64 returns the JSON number at keys, or 0 if the key is missing
+func optionalInt64(msi any, keys []string) (int64, error) {
+	value, _ := web.MsiValued(msi, keys, json.Number("0"))
+	n, err := common.JSONInt64(value)
 	if err != nil {
-		panic(fmt.Errorf("item missing from %v: %w", msi, err))
+		return 0, fmt.Errorf("%s: %w", strings.Join(keys, "."), err)
 	}
-	return common.JSONInt64Panic(value)
+	return n, nil
+}
+
+func auctionID(msi any) (int64, error) {
+	return jsonInt64(msi, []string{"id"})
+}
+
+func itemID(msi any) (int64, error) {
+	return jsonInt64(msi, []string{"item", "id"})
 }
 
-func buyout(msi any) int64 {
+func buyout(msi any) (int64, error) {
 	value, err := web.MsiValue(msi, []string{"buyout"})
 	if value == nil || err != nil {
-		value, err = web.MsiValued(msi, []string{"unit_price"}, json.Number("0"))
-		if err != nil {
-			// Some auctions have neither 'buyout' nor 'unit_price'. Strange, but true.
-			return 0
-		}
+		// Some auctions have neither 'buyout' nor 'unit_price'. Strange, but true.
+		return optionalInt64(msi, []string{"unit_price"})
 	}
-	return common.JSONInt64Panic(value)
-}
-
-func quantity(msi any) int64 {
-	value, err := web.MsiValued(msi, []string{"quantity"}, 0)
+	n, err := common.JSONInt64(value)
 	if err != nil {
-		panic(fmt.Errorf("quantity missing from %v: %w", msi, err))
+		return 0, fmt.Errorf("buyout: %w", err)
 	}
-	return common.JSONInt64Panic(value)
+	return n, nil
 }
 
-func petLevel(msi any) int64 {
-	value, err := web.MsiValue(msi, []string{"item", "pet_level"})
-	if err != nil {
-		panic(fmt.Errorf("pet_level missing from %v: %w", msi, err))
-	}
-	return common.JSONInt64Panic(value)
+func bid(msi any) (int64, error) {
+	// Only auctions that accept bids have one
+	return optionalInt64(msi, []string{"bid"})
 }
 
-func petQualityID(msi any) int64 {
-	value, err := web.MsiValue(msi, []string{"item", "pet_quality_id"})
-	if err != nil {
-		panic(fmt.Errorf("pet_quality_id missing from %v: %w", msi, err))
+func timeLeft(msi any) TimeLeft {
+	value, _ := web.MsiValued(msi, []string{"time_left"}, "")
+	name, _ := value.(string)
+	// An unrecognized bucket is TimeLeftUnknown; callers treat it as never expiring soon
+	return timeLeftNames[name]
+}
+
+func quantity(msi any) (int64, error) {
+	return jsonInt64(msi, []string{"quantity"})
+}
+
+func petLevel(msi any) (int64, error) {
+	return jsonInt64(msi, []string{"item", "pet_level"})
+}
+
+func petQualityID(msi any) (int64, error) {
+	return jsonInt64(msi, []string{"item", "pet_quality_id"})
+}
+
+func petSpeciesID(msi any) (int64, error) {
+	return jsonInt64(msi, []string{"item", "pet_species_id"})
+}
+
+func bonusLists(msi any) ([]int64, error) {
+	// Only some items have bonuses; do not error if the key is missing
+	value, _ := web.MsiValued(msi, []string{"item", "bonus_lists"}, []any{})
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("bonus_lists: expected list, got %T", value)
 	}
-	return common.JSONInt64Panic(value)
+
+	bonuses := []int64{}
+	for _, bonus := range list {
+		n, err := common.JSONInt64(bonus)
+		if err != nil {
+			return nil, fmt.Errorf("bonus_lists: %w", err)
+		}
+		bonuses = append(bonuses, n)
+	}
+	return bonuses, nil
 }
 
-func petSpeciesID(msi any) int64 {
-	value, err := web.MsiValue(msi, []string{"item", "pet_species_id"})
-	if err != nil {
-		panic(fmt.Errorf("pet_species_id missing from %v: %w", msi, err))
+func modifiers(msi any) (map[ModifierType]int64, error) {
+	// Only some items have modifiers; do not error if the key is missing
+	value, _ := web.MsiValued(msi, []string{"item", "modifiers"}, []any{})
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("modifiers: expected list, got %T", value)
 	}
-	return common.JSONInt64Panic(value)
+
+	mods := map[ModifierType]int64{}
+	for _, modifier := range list {
+		t, err := jsonInt64(modifier, []string{"type"})
+		if err != nil {
+			return nil, fmt.Errorf("modifier: %w", err)
+		}
+		v, err := jsonInt64(modifier, []string{"value"})
+		if err != nil {
+			return nil, fmt.Errorf("modifier: %w", err)
+		}
+		mods[ModifierType(t)] = v
+	}
+	return mods, nil
+}
+
+func itemContext(msi any) (int64, error) {
+	// Only some items have a context; do not error if the key is missing
+	return optionalInt64(msi, []string{"item", "context"})
 }
 
-// newAuction converts a single auction JSON string into a struct that is much easier to work with
-func newAuction(auc any) Auction {
+// newAuction converts a single auction JSON string into a struct that is much easier to work with.
+// On error the returned auction holds whatever was parsed before the malformed field.
+func newAuction(auc any) (Auction, error) {
 	var a Auction
+	var err error
+
+	if a.ID, err = auctionID(auc); err != nil {
+		return a, err
+	}
+	if a.ItemID, err = itemID(auc); err != nil {
+		return a, fmt.Errorf("auction %d: %w", a.ID, err)
+	}
 
-	a.ID = auctionID(auc)
-	a.ItemID = itemID(auc)
-	a.Buyout = buyout(auc)
-	a.Quantity = quantity(auc)
+	wrap := func(err error) (Auction, error) {
+		return a, fmt.Errorf("auction %d: %w", a.ID, err)
+	}
+
+	if a.Buyout, err = buyout(auc); err != nil {
+		return wrap(err)
+	}
+	if a.Bid, err = bid(auc); err != nil {
+		return wrap(err)
+	}
+	if a.Quantity, err = quantity(auc); err != nil {
+		return wrap(err)
+	}
+	a.TimeLeft = timeLeft(auc)
+	if a.BonusLists, err = bonusLists(auc); err != nil {
+		return wrap(err)
+	}
+	if a.Modifiers, err = modifiers(auc); err != nil {
+		return wrap(err)
+	}
+	if a.Context, err = itemContext(auc); err != nil {
+		return wrap(err)
+	}
 
 	// Is this a Pet Cage?
 	if a.ItemID == battlepet.PetCageItemID {
 		// A pet auction!
-		a.Pet.Level = petLevel(auc)
-		a.Pet.QualityID = petQualityID(auc)
-		a.Pet.SpeciesID = petSpeciesID(auc)
+		if a.Pet.Level, err = petLevel(auc); err != nil {
+			return wrap(err)
+		}
+		if a.Pet.QualityID, err = petQualityID(auc); err != nil {
+			return wrap(err)
+		}
+		if a.Pet.SpeciesID, err = petSpeciesID(auc); err != nil {
+			return wrap(err)
+		}
 	}
 
-	return a
+	return a, nil
+}
+
+// House holds one realm's auctions
+type House struct {
+	Auctions map[int64][]Auction // Auctions with a buyout, binned by item ID
+	BidOnly  map[int64][]Auction // Auctions that accept bids, but not purchases, binned by item ID
+
+	// Diagnostics holds the auctions that could not be parsed. They are left out of the bins.
+	Diagnostics []diagnostic.Diagnostic
 }
 
-// bin bins auctions by itemID
-func bin(auctions []any) map[int64][]Auction {
-	bins := map[int64][]Auction{}
+// bin bins auctions by itemID. Auctions that accept bids, but not purchases, go in a bin of their own.
+func bin(realm string, auctions []any) House {
+	house := House{
+		Auctions: map[int64][]Auction{},
+		BidOnly:  map[int64][]Auction{},
+	}
 
 	for _, auc := range auctions {
-		aucStruct := newAuction(auc.(map[string]any))
+		aucStruct, err := newAuction(auc)
+		if err != nil {
+			house.Diagnostics = append(house.Diagnostics, diagnostic.Diagnostic{Realm: realm, ItemID: aucStruct.ItemID, Err: err})
+			continue
+		}
 		if aucStruct.Buyout <= 0 {
-			// These accept bids, but not purchases. Ignore these.
+			if aucStruct.Bid > 0 {
+				house.BidOnly[aucStruct.ItemID] = append(house.BidOnly[aucStruct.ItemID], aucStruct)
+			}
 			continue
 		}
-		bins[aucStruct.ItemID] = append(bins[aucStruct.ItemID], aucStruct)
+		house.Auctions[aucStruct.ItemID] = append(house.Auctions[aucStruct.ItemID], aucStruct)
 	}
 
-	return bins
+	return house
 }
 
-// Get returns the current auctions binned by item ID
-func Get(realm string) (map[int64][]Auction, error) {
+// Get returns the current auctions for realm
+func Get(realm string) (House, error) {
 	var err error
 	var auctions []any
 
@@ -144,8 +329,8 @@ func Get(realm string) (map[int64][]Auction, error) {
 		auctions, err = wowapi.Auctions(realm)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("unable to obtain auctions for %s: %w", realm, err)
+		return House{}, fmt.Errorf("unable to obtain auctions for %s: %w", realm, err)
 	}
 
-	return bin(auctions), nil
+	return bin(realm, auctions), nil
 }
@@ -2,8 +2,11 @@ package auction
 
 import (
 	"encoding/json"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/erikbryant/wow/internal/battlepet"
 )
@@ -17,9 +20,19 @@ func auction(id, item, buyout, qty int64) map[string]any {
 	return map[string]any{"id": jsonNumber(id), "item": map[string]any{"id": jsonNumber(item)}, "buyout": jsonNumber(buyout), "quantity": jsonNumber(qty)}
 }
 
+func parse(t *testing.T, a any) Auction {
+	t.Helper()
+
+	got, err := newAuction(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
 func TestNewAuction(t *testing.T) {
 	a := auction(11, 22, 333, 4)
-	got := newAuction(a)
+	got := parse(t, a)
 	if got.ID != 11 || got.ItemID != 22 || got.Buyout != 333 || got.Quantity != 4 {
 		t.Fatalf("%+v", got)
 	}
@@ -27,7 +40,7 @@ func TestNewAuction(t *testing.T) {
 
 func TestNewAuctionCommodity(t *testing.T) {
 	a := map[string]any{"id": jsonNumber(1), "item": map[string]any{"id": jsonNumber(2)}, "unit_price": jsonNumber(99), "quantity": jsonNumber(7)}
-	got := newAuction(a)
+	got := parse(t, a)
 	if got.Buyout != 99 || got.Quantity != 7 {
 		t.Fatalf("%+v", got)
 	}
@@ -38,10 +51,52 @@ func TestNewAuctionPet(t *testing.T) {
 	a["item"].(map[string]any)["pet_level"] = jsonNumber(25)
 	a["item"].(map[string]any)["pet_quality_id"] = jsonNumber(3)
 	a["item"].(map[string]any)["pet_species_id"] = jsonNumber(1446)
-	got := newAuction(a)
+	a["item"].(map[string]any)["modifiers"] = []any{map[string]any{"type": jsonNumber(6), "value": jsonNumber(39130)}}
+	got := parse(t, a)
 	if got.Pet.Level != 25 || got.Pet.QualityID != 3 || got.Pet.SpeciesID != 1446 {
 		t.Fatalf("%+v", got.Pet)
 	}
+	if got.Modifiers[ModifierBattlePetDisplayID] != 39130 {
+		t.Errorf("modifiers = %v", got.Modifiers)
+	}
+}
+
+func TestNewAuctionBonuses(t *testing.T) {
+	a := auction(1, 238011, 4500000, 1)
+	a["item"].(map[string]any)["bonus_lists"] = []any{jsonNumber(12345), jsonNumber(12217)}
+	a["item"].(map[string]any)["modifiers"] = []any{map[string]any{"type": jsonNumber(9), "value": jsonNumber(80)}}
+	a["item"].(map[string]any)["context"] = jsonNumber(13)
+	got := parse(t, a)
+	if !got.HasBonuses() || !slices.Equal(got.BonusLists, []int64{12345, 12217}) || got.Modifiers[ModifierTimewalkerLevel] != 80 || len(got.Modifiers) != 1 || got.Context != 13 {
+		t.Fatalf("%+v", got)
+	}
+
+	got = parse(t, auction(1, 22, 333, 4))
+	if len(got.BonusLists) != 0 || len(got.Modifiers) != 0 || got.Context != 0 {
+		t.Fatalf("no bonuses: %+v", got)
+	}
+}
+
+func TestNewAuctionTimeLeftAndBid(t *testing.T) {
+	a := map[string]any{"id": jsonNumber(1), "item": map[string]any{"id": jsonNumber(2)}, "bid": jsonNumber(1500), "quantity": jsonNumber(1), "time_left": "SHORT"}
+	got := parse(t, a)
+	if got.Bid != 1500 || got.Buyout != 0 || got.TimeLeft != TimeLeftShort || got.TimeLeft.String() != "SHORT" {
+		t.Fatalf("%+v", got)
+	}
+	if !got.ExpiresWithin(time.Hour) || got.ExpiresWithin(10*time.Minute) {
+		t.Errorf("SHORT auction: ExpiresWithin(1h)=%t ExpiresWithin(10m)=%t", got.ExpiresWithin(time.Hour), got.ExpiresWithin(10*time.Minute))
+	}
+
+	a["time_left"] = "SOMEDAY"
+	got = parse(t, a)
+	if got.TimeLeft != TimeLeftUnknown || got.ExpiresWithin(48*time.Hour) {
+		t.Errorf("unknown time left: %+v", got)
+	}
+
+	got = parse(t, auction(1, 22, 333, 4))
+	if got.Bid != 0 || got.TimeLeft != TimeLeftUnknown || got.HasBonuses() {
+		t.Errorf("%+v", got)
+	}
 }
 
 func TestBuyoutMissing(t *testing.T) {
@@ -50,7 +105,7 @@ func TestBuyoutMissing(t *testing.T) {
 		{"id": jsonNumber(1), "item": map[string]any{"id": jsonNumber(2)}, "quantity": jsonNumber(1), "buyout": nil},
 		{"id": jsonNumber(1), "item": map[string]any{"id": jsonNumber(2)}, "quantity": jsonNumber(1), "buyout": jsonNumber(0), "unit_price": jsonNumber(17)},
 	} {
-		if got := newAuction(data).Buyout; got != 0 && got != 17 {
+		if got := parse(t, data).Buyout; got != 0 && got != 17 {
 			t.Errorf("buyout=%d", got)
 		}
 	}
@@ -59,8 +114,47 @@ func TestBuyoutMissing(t *testing.T) {
 func TestBin(t *testing.T) {
 	valid := auction(1, 100, 10, 1)
 	badPrice := auction(2, 100, 0, 1)
-	got := bin([]any{valid, badPrice})
+	bidOnly := auction(3, 100, 0, 1)
+	bidOnly["bid"] = jsonNumber(50)
+	malformed := auction(4, 200, 10, 1)
+	delete(malformed, "quantity")
+	noItem := map[string]any{"id": jsonNumber(5), "buyout": jsonNumber(10), "quantity": jsonNumber(1)}
+	house := bin("Realm", []any{valid, badPrice, bidOnly, malformed, noItem})
+	got, bids := house.Auctions, house.BidOnly
 	if len(got) != 1 || len(got[100]) != 1 || got[100][0].ID != 1 {
 		t.Fatalf("%+v", got)
 	}
+	if len(bids) != 1 || len(bids[100]) != 1 || bids[100][0].ID != 3 || bids[100][0].Bid != 50 {
+		t.Fatalf("bid-only = %+v", bids)
+	}
+	if len(house.Diagnostics) != 2 {
+		t.Fatalf("diagnostics = %v", house.Diagnostics)
+	}
+	if d := house.Diagnostics[0]; d.Realm != "Realm" || d.ItemID != 200 || !strings.Contains(d.Err.Error(), "quantity") {
+		t.Errorf("malformed: %v", d)
+	}
+	if d := house.Diagnostics[1]; d.ItemID != 0 || !strings.Contains(d.Err.Error(), "auction 5") {
+		t.Errorf("no item: %v", d)
+	}
+}
+
+func TestNewAuctionMalformed(t *testing.T) {
+	pet := auction(1, battlepet.PetCageItemID, 1000, 1)
+	badBonus := auction(2, 22, 333, 4)
+	badBonus["item"].(map[string]any)["bonus_lists"] = []any{"x"}
+	badModifier := auction(3, 22, 333, 4)
+	badModifier["item"].(map[string]any)["modifiers"] = []any{map[string]any{"type": jsonNumber(9)}}
+	badID := auction(4, 22, 333, 4)
+	badID["id"] = "four"
+
+	for name, a := range map[string]map[string]any{
+		"pet without species": pet,
+		"bonus not a number":  badBonus,
+		"modifier no value":   badModifier,
+		"id not a number":     badID,
+	} {
+		if _, err := newAuction(a); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
 }
@@ -2,20 +2,32 @@ package battlepet
 
 import (
 	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/erikbryant/web"
 	"github.com/erikbryant/wow/internal/common"
 	"github.com/erikbryant/wow/internal/wowapi"
 	"github.com/erikbryant/wow/internal/wowitem"
 )
 
 type BattlePet struct {
-	names map[int64]string
-	owned map[int64]int64
+	names  map[int64]string
+	byName map[string][]int64 // Species IDs by name, sorted; a few names are shared
+	owned  map[int64]int64
+
+	// cageable is looked up one species at a time, only when needed
+	mu       sync.Mutex
+	cageable map[int64]bool
 }
 
 const (
 	PetCageItemID = int64(82800)
+
+	// MaxPerSpecies is the most pets of a single species the game lets you learn
+	MaxPerSpecies = int64(3)
 )
 
 // getPetNames downloads all pet names from the WoW web API
@@ -28,9 +40,15 @@ func getPetNames() (map[int64]string, error) {
 	}
 
 	for _, petRaw := range allPets {
-		pet := petRaw.(map[string]any)
-		id := common.JSONInt64Panic(pet["id"])
-		names[id] = pet["name"].(string)
+		pet, ok := petRaw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("battle pet has type %T, want map[string]any", petRaw)
+		}
+		id, err := common.JSONInt64(pet["id"])
+		if err != nil {
+			return nil, fmt.Errorf("battle pet %v id: %w", pet["name"], err)
+		}
+		names[id] = common.JSONString(pet["name"])
 	}
 
 	return names, nil
@@ -46,13 +64,19 @@ func getPetsOwned() (map[int64]int64, error) {
 	}
 
 	for _, petRaw := range pets {
-		pet := petRaw.(map[string]any)
+		pet, ok := petRaw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("battle pet owned has type %T, want map[string]any", petRaw)
+		}
 
 		species, ok := pet["species"].(map[string]any)
 		if !ok {
 			return nil, fmt.Errorf("unable to obtain battle pet species")
 		}
-		speciesID := common.JSONInt64Panic(species["id"])
+		speciesID, err := common.JSONInt64(species["id"])
+		if err != nil {
+			return nil, fmt.Errorf("battle pet species id: %w", err)
+		}
 
 		owned[speciesID]++
 	}
@@ -60,6 +84,21 @@ func getPetsOwned() (map[int64]int64, error) {
 	return owned, nil
 }
 
+// indexNames returns the species IDs for each pet name
+func indexNames(names map[int64]string) map[string][]int64 {
+	byName := map[string][]int64{}
+
+	for petID, petName := range names {
+		byName[petName] = append(byName[petName], petID)
+	}
+
+	for _, petIDs := range byName {
+		slices.Sort(petIDs)
+	}
+
+	return byName
+}
+
 func New() (*BattlePet, error) {
 	var err error
 	bp := BattlePet{}
@@ -68,6 +107,7 @@ func New() (*BattlePet, error) {
 	if err != nil {
 		return nil, err
 	}
+	bp.byName = indexNames(bp.names)
 
 	bp.owned, err = getPetsOwned()
 	if err != nil {
@@ -77,19 +117,21 @@ func New() (*BattlePet, error) {
 	return &bp, nil
 }
 
-// PetSpell returns true and the corresponding pet ID if the item is a pet summoning spell
+// PetSpell returns true and the corresponding pet ID if the item is a pet summoning spell.
+// Neither the item nor the pet API says which of several same-named species an item
+// teaches, so an item whose name is shared by more than one species returns false
+// rather than a guess that might report a pet I own as needed.
 func (bp *BattlePet) PetSpell(i wowitem.Item) (int64, bool) {
 	if i.ItemSubclassName() != "Companion Pets" {
 		return 0, false
 	}
 
-	for petID, petName := range bp.names {
-		if petName == i.Name() {
-			return petID, true
-		}
+	petIDs := bp.byName[i.Name()]
+	if len(petIDs) != 1 {
+		return 0, false
 	}
 
-	return 0, false
+	return petIDs[0], true
 }
 
 // Name returns the pet name for the given ID
@@ -102,13 +144,60 @@ func (bp *BattlePet) Owned(petID int64) bool {
 	return bp.owned[petID] > 0
 }
 
-// Output returns all petID/names
+// Count returns how many pets of this species I have learned
+func (bp *BattlePet) Count(petID int64) int64 {
+	return bp.owned[petID]
+}
+
+// SlotsRemaining returns how many more pets of this species I can learn
+func (bp *BattlePet) SlotsRemaining(petID int64) int64 {
+	return max(MaxPerSpecies-bp.owned[petID], 0)
+}
+
+// AtCap returns true if I cannot learn any more pets of this species
+func (bp *BattlePet) AtCap(petID int64) bool {
+	return bp.SlotsRemaining(petID) == 0
+}
+
+// Cageable returns true if pets of this species can be caged (and so traded)
+func (bp *BattlePet) Cageable(petID int64) bool {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	cageable, ok := bp.cageable[petID]
+	if ok {
+		return cageable
+	}
+
+	pet, err := wowapi.Pet(petID)
+	if err != nil {
+		// Do not cache; maybe the next lookup will succeed
+		fmt.Fprintf(os.Stderr, "*** unable to determine whether pet %d is cageable: %v\n", petID, err)
+		return true
+	}
+
+	tradable, _ := web.MsiValued(pet, []string{"is_tradable"}, true)
+	cageable, ok = tradable.(bool)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "*** pet %d has unexpected is_tradable: %v\n", petID, tradable)
+		cageable = true
+	}
+
+	if bp.cageable == nil {
+		bp.cageable = map[int64]bool{}
+	}
+	bp.cageable[petID] = cageable
+
+	return cageable
+}
+
+// Output returns all petID/names along with how many more of each I can learn
 func (bp *BattlePet) Output() string {
 	var output strings.Builder
 
 	for petID, petName := range bp.names {
 		// Output in a format pastable into shopping.SkipPets
-		output.WriteString(fmt.Sprintf("%d: {}, // %s\n", petID, petName))
+		output.WriteString(fmt.Sprintf("%d: {}, // %s (owned %d/%d, slots remaining %d)\n", petID, petName, bp.Count(petID), MaxPerSpecies, bp.SlotsRemaining(petID)))
 	}
 
 	return output.String()
@@ -11,7 +11,8 @@ func bpItem(name, subclass string) wowitem.Item {
 	return wowitem.Item{XID: 1, XItem: map[string]any{"name": name, "item_subclass": map[string]any{"name": subclass}}}
 }
 func TestBattlePetMethods(t *testing.T) {
-	bp := &BattlePet{names: map[int64]string{10: "Cat", 20: "Dog"}, owned: map[int64]int64{10: 2}}
+	names := map[int64]string{10: "Cat", 20: "Dog"}
+	bp := &BattlePet{names: names, byName: indexNames(names), owned: map[int64]int64{10: 2}}
 	if n, ok := bp.PetSpell(bpItem("Cat", "Companion Pets")); !ok || n != 10 {
 		t.Fail()
 	}
@@ -28,7 +29,38 @@ func TestBattlePetMethods(t *testing.T) {
 		t.Fail()
 	}
 	out := bp.Output()
-	if !strings.Contains(out, "10: {}, // Cat") || !strings.Contains(out, "20: {}, // Dog") {
+	if !strings.Contains(out, "10: {}, // Cat (owned 2/3, slots remaining 1)") || !strings.Contains(out, "20: {}, // Dog") {
 		t.Fatalf("%s", out)
 	}
 }
+
+func TestPetSpellSharedName(t *testing.T) {
+	names := map[int64]string{10: "Twin", 20: "Twin"}
+	bp := &BattlePet{names: names, byName: indexNames(names), owned: map[int64]int64{10: 1}}
+	if n, ok := bp.PetSpell(bpItem("Twin", "Companion Pets")); ok {
+		t.Errorf("a shared name is ambiguous, got %d", n)
+	}
+}
+
+func TestSpeciesCap(t *testing.T) {
+	bp := &BattlePet{owned: map[int64]int64{10: 1, 20: MaxPerSpecies, 30: MaxPerSpecies + 1}}
+	if bp.Count(10) != 1 || bp.Count(99) != 0 {
+		t.Fail()
+	}
+	if bp.SlotsRemaining(10) != MaxPerSpecies-1 || bp.SlotsRemaining(99) != MaxPerSpecies {
+		t.Fail()
+	}
+	if bp.SlotsRemaining(20) != 0 || bp.SlotsRemaining(30) != 0 {
+		t.Error("a full species should have no slots remaining")
+	}
+	if bp.AtCap(10) || !bp.AtCap(20) || !bp.AtCap(30) {
+		t.Fail()
+	}
+}
+
+func TestCageableCached(t *testing.T) {
+	bp := &BattlePet{cageable: map[int64]bool{10: false, 20: true}}
+	if bp.Cageable(10) || !bp.Cageable(20) {
+		t.Fail()
+	}
+}
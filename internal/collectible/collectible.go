@@ -0,0 +1,341 @@
+package collectible
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/erikbryant/web"
+	"github.com/erikbryant/wow/internal/common"
+	"github.com/erikbryant/wow/internal/persist"
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+// Collectible is an account-wide collection (toys, mounts, heirlooms, ...)
+type Collectible interface {
+	// Owned returns true if the item teaches a collectible and we own it
+	Owned(i wowitem.Item) bool
+	// Need returns true if the item teaches a collectible we do not own
+	Need(i wowitem.Item) bool
+	// LenIndex returns the number of collectibles in the game
+	LenIndex() int
+	// LenOwned returns the number of collectibles we own
+	LenOwned() int
+}
+
+// Source describes how to load one kind of collectible from the WoW web API.
+// Adding a new kind of collectible only requires a new Source.
+type Source struct {
+	// Kind names the collectible in diagnostics (e.g. "toy")
+	Kind string
+	// Index returns every collectible in the game, each with an "id" and "name"
+	Index func() ([]any, error)
+	// Owned returns the collectibles we own
+	Owned func() ([]any, error)
+	// OwnedKey is the key in each Owned entry that holds the collectible's "id"
+	OwnedKey string
+	// ItemOf returns the item that teaches a collectible. Optional; if set, New
+	// indexes every collectible by its item and items are matched by ID, not name.
+	// The index is saved, so ItemOf is only called for collectibles new to it.
+	ItemOf func(id int64) (int64, error)
+	// Teaches returns true if the item could teach this kind of collectible. Optional.
+	Teaches func(i wowitem.Item) bool
+	// Names returns the names of the collectibles the item might teach, for sources
+	// without ItemOf. Optional; defaults to the item's own name.
+	Names func(i wowitem.Item) []string
+}
+
+// ItemOfDetail returns an ItemOf that reads the teaching item from a
+// collectible's detail endpoint (e.g. /data/wow/toy/{id})
+func ItemOfDetail(detail func(id int64) (map[string]any, error)) func(id int64) (int64, error) {
+	return func(id int64) (int64, error) {
+		d, err := detail(id)
+		if err != nil {
+			return 0, err
+		}
+
+		itemID, err := web.MsiValue(d, []string{"item", "id"})
+		if err != nil {
+			return 0, fmt.Errorf("%d has no item: %w", id, err)
+		}
+
+		return common.JSONInt64(itemID)
+	}
+}
+
+// indexWorkers is how many teaching items are looked up at once while building the item index
+const indexWorkers = 8
+
+// Collection holds the collectibles of a single kind and which of those we own
+type Collection struct {
+	source Source
+
+	// Collectible IDs. These are not the same as item IDs.
+	byName map[string][]int64
+	owned  map[int64]bool
+
+	// byItem maps each item ID to the collectibles it teaches. It is built once, and
+	// only if the source has ItemOf; otherwise items are matched by name.
+	byItem map[int64][]int64
+	// unindexed holds the collectibles whose teaching item could not be looked up.
+	// They are still matched by name.
+	unindexed map[int64]bool
+}
+
+// getNames returns the collectible IDs for each name, sorted by ID
+func getNames(source Source) (map[string][]int64, error) {
+	names := map[string][]int64{}
+
+	all, err := source.Index()
+	if err != nil {
+		return nil, fmt.Errorf("unable to obtain %s names: %w", source.Kind, err)
+	}
+
+	for _, raw := range all {
+		entry, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s entry has type %T, want map[string]any", source.Kind, raw)
+		}
+		id, err := common.JSONInt64(entry["id"])
+		if err != nil {
+			return nil, fmt.Errorf("%s %v id: %w", source.Kind, entry["name"], err)
+		}
+		name := common.JSONString(entry["name"])
+		names[name] = append(names[name], id)
+	}
+
+	for _, ids := range names {
+		slices.Sort(ids)
+	}
+
+	return names, nil
+}
+
+// getOwned returns the collectibles I own by ID
+func getOwned(source Source) (map[int64]bool, error) {
+	owned := map[int64]bool{}
+
+	all, err := source.Owned()
+	if err != nil {
+		return nil, fmt.Errorf("unable to obtain %ss owned: %w", source.Kind, err)
+	}
+
+	for _, raw := range all {
+		id, _ := web.MsiValued(raw, []string{source.OwnedKey, "id"}, 0)
+		n, err := common.JSONInt64(id)
+		if err != nil {
+			return nil, fmt.Errorf("%s owned id: %w", source.Kind, err)
+		}
+		owned[n] = true
+	}
+
+	return owned, nil
+}
+
+// indexItems indexes the collectibles by the item that teaches them. Teaching items
+// already in index are reused; the rest are looked up with a pool of workers and added.
+func (c *Collection) indexItems(index *persist.Persistence[int64, int64]) {
+	if c.source.ItemOf == nil {
+		return
+	}
+
+	c.byItem = map[int64][]int64{}
+	c.unindexed = map[int64]bool{}
+
+	missing := []int64{}
+	for _, ids := range c.byName {
+		for _, id := range ids {
+			if itemID, ok := index.Get(id); ok {
+				c.byItem[itemID] = append(c.byItem[itemID], id)
+				continue
+			}
+			missing = append(missing, id)
+		}
+	}
+
+	type lookup struct {
+		id     int64
+		itemID int64
+		err    error
+	}
+
+	jobs := make(chan int64)
+	results := make(chan lookup)
+
+	var wg sync.WaitGroup
+	for range indexWorkers {
+		wg.Go(func() {
+			for id := range jobs {
+				itemID, err := c.source.ItemOf(id)
+				results <- lookup{id: id, itemID: itemID, err: err}
+			}
+		})
+	}
+
+	go func() {
+		for _, id := range missing {
+			jobs <- id
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var lookupErr error
+	for r := range results {
+		if r.err != nil {
+			// Not saved, so it is looked up again next time
+			c.unindexed[r.id] = true
+			lookupErr = r.err
+			continue
+		}
+		index.Set(r.id, r.itemID)
+		c.byItem[r.itemID] = append(c.byItem[r.itemID], r.id)
+	}
+
+	if len(c.unindexed) > 0 {
+		fmt.Fprintf(os.Stderr, "*** unable to look up the item for %d %ss, matching them by name: %v\n", len(c.unindexed), c.source.Kind, lookupErr)
+	}
+
+	for _, ids := range c.byItem {
+		slices.Sort(ids)
+	}
+}
+
+// New loads the full index and what we own for one kind of collectible. If the source
+// has ItemOf, it also indexes the collectibles by the item that teaches them, keeping
+// that index in itemsPath (see persist.New) between runs.
+func New(source Source, itemsPath string) (*Collection, error) {
+	var err error
+	c := Collection{
+		source: source,
+	}
+
+	c.byName, err = getNames(source)
+	if err != nil {
+		return nil, err
+	}
+
+	c.owned, err = getOwned(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.ItemOf == nil {
+		return &c, nil
+	}
+
+	index := persist.New[int64, int64](itemsPath)
+	err = index.Load()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("unable to load %s items: %w", source.Kind, err)
+	}
+
+	c.indexItems(index)
+
+	if index.Dirty() {
+		err = index.Save()
+		if err != nil {
+			return nil, fmt.Errorf("unable to save %s items: %w", source.Kind, err)
+		}
+	}
+
+	return &c, nil
+}
+
+// NewWithData returns a Collection from already loaded data. It is only used for tests.
+func NewWithData(source Source, byName map[string][]int64, owned map[int64]bool) *Collection {
+	c := &Collection{
+		source: source,
+		byName: byName,
+		owned:  owned,
+	}
+
+	// The index starts empty and is never saved
+	c.indexItems(persist.New[int64, int64](""))
+
+	return c
+}
+
+// byNames returns the IDs of the collectibles named by the item
+func (c *Collection) byNames(i wowitem.Item) []int64 {
+	if c.source.Names == nil {
+		return c.byName[i.Name()]
+	}
+
+	ids := []int64{}
+	for _, name := range c.source.Names(i) {
+		for _, id := range c.byName[name] {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids
+}
+
+// ids returns the IDs of the collectibles the item might teach
+func (c *Collection) ids(i wowitem.Item) []int64 {
+	if c.source.Teaches != nil && !c.source.Teaches(i) {
+		return nil
+	}
+
+	if c.byItem == nil {
+		return c.byNames(i)
+	}
+
+	ids, ok := c.byItem[i.ID()]
+	if ok {
+		return ids
+	}
+
+	// The item may teach a collectible whose teaching item could not be looked up
+	ids = nil
+	for _, id := range c.byName[i.Name()] {
+		if c.unindexed[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// Owned returns true if the item teaches a collectible and we own it. When the
+// item cannot be told apart from others of the same name, we must own them all.
+func (c *Collection) Owned(i wowitem.Item) bool {
+	ids := c.ids(i)
+	if len(ids) == 0 {
+		return false
+	}
+
+	for _, id := range ids {
+		if !c.owned[id] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Need returns true if the item teaches a collectible we do not own
+func (c *Collection) Need(i wowitem.Item) bool {
+	return len(c.ids(i)) > 0 && !c.Owned(i)
+}
+
+// LenIndex returns the number of collectibles in the game.
+func (c *Collection) LenIndex() int {
+	count := 0
+	for _, ids := range c.byName {
+		count += len(ids)
+	}
+	return count
+}
+
+// LenOwned returns the number of collectibles we own.
+func (c *Collection) LenOwned() int {
+	return len(c.owned)
+}
@@ -0,0 +1,184 @@
+package collectible
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+func item(id int64, name string) wowitem.Item {
+	return wowitem.Item{XID: id, XItem: map[string]any{"name": name}}
+}
+
+func TestNew(t *testing.T) {
+	source := Source{
+		Kind: "widget",
+		Index: func() ([]any, error) {
+			return []any{
+				map[string]any{"id": json.Number("2"), "name": "B"},
+				map[string]any{"id": json.Number("1"), "name": "A"},
+				map[string]any{"id": json.Number("3"), "name": "A"},
+			}, nil
+		},
+		Owned: func() ([]any, error) {
+			return []any{map[string]any{"widget": map[string]any{"id": json.Number("2")}}}, nil
+		},
+		OwnedKey: "widget",
+	}
+
+	c, err := New(source, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.LenIndex() != 3 || c.LenOwned() != 1 {
+		t.Fatalf("LenIndex=%d LenOwned=%d", c.LenIndex(), c.LenOwned())
+	}
+	if ids := c.byName["A"]; len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Fatalf("byName[A]=%v", ids)
+	}
+	if !c.Owned(item(10, "B")) || c.Need(item(10, "B")) {
+		t.Error("owned widget")
+	}
+	if c.Owned(item(11, "A")) || !c.Need(item(11, "A")) {
+		t.Error("unowned widget")
+	}
+	if c.Need(item(12, "Z")) {
+		t.Error("not a widget")
+	}
+}
+
+func TestNewIndexError(t *testing.T) {
+	source := Source{
+		Kind:  "widget",
+		Index: func() ([]any, error) { return nil, errors.New("boom") },
+	}
+	if _, err := New(source, ""); err == nil {
+		t.Fatal("expected error")
+	}
+
+	source.Index = func() ([]any, error) { return []any{"not an object"}, nil }
+	if _, err := New(source, ""); err == nil {
+		t.Fatal("a malformed entry should be an error")
+	}
+}
+
+func TestSharedNameItemOf(t *testing.T) {
+	var mu sync.Mutex
+	lookups := 0
+	source := Source{
+		Kind: "widget",
+		ItemOf: func(id int64) (int64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			lookups++
+			return id * 10, nil
+		},
+	}
+	c := NewWithData(source, map[string][]int64{"Twin": {1, 2}, "Solo": {3}}, map[int64]bool{2: true})
+	if lookups != 3 {
+		t.Errorf("lookups = %d, want one per collectible", lookups)
+	}
+
+	if !c.Owned(item(20, "Twin")) {
+		t.Error("item 20 teaches the owned twin")
+	}
+	if c.Owned(item(10, "Twin")) || !c.Need(item(10, "Twin")) {
+		t.Error("item 10 teaches the unowned twin")
+	}
+	if !c.Need(item(30, "A Differently Named Item")) {
+		t.Error("items are matched by the item index, not their name")
+	}
+	if c.Need(item(99, "Solo")) {
+		t.Error("an item outside the index teaches nothing")
+	}
+	if lookups != 3 {
+		t.Error("lookups should all happen when the collection is built")
+	}
+}
+
+func TestSavedItemIndex(t *testing.T) {
+	var mu sync.Mutex
+	lookups := 0
+	index := []any{
+		map[string]any{"id": json.Number("1"), "name": "A"},
+		map[string]any{"id": json.Number("2"), "name": "B"},
+	}
+	source := Source{
+		Kind:     "widget",
+		Index:    func() ([]any, error) { return index, nil },
+		Owned:    func() ([]any, error) { return []any{}, nil },
+		OwnedKey: "widget",
+		ItemOf: func(id int64) (int64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			lookups++
+			return id * 10, nil
+		},
+	}
+	itemsPath := t.TempDir() + "/widgetItems"
+
+	if _, err := New(source, itemsPath); err != nil {
+		t.Fatal(err)
+	}
+	if lookups != 2 {
+		t.Fatalf("lookups = %d, want 2", lookups)
+	}
+
+	// The next run only looks up the widget it has not seen
+	index = append(index, map[string]any{"id": json.Number("3"), "name": "C"})
+	c, err := New(source, itemsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lookups != 3 {
+		t.Fatalf("lookups = %d, want 3", lookups)
+	}
+	if !c.Need(item(10, "Renamed")) || !c.Need(item(30, "C")) {
+		t.Error("saved and new widgets should both be matched by item")
+	}
+}
+
+func TestItemOfError(t *testing.T) {
+	source := Source{
+		Kind: "widget",
+		ItemOf: func(id int64) (int64, error) {
+			if id == 2 {
+				return 0, errors.New("timeout")
+			}
+			return id * 10, nil
+		},
+	}
+	c := NewWithData(source, map[string][]int64{"A": {1}, "B": {2}}, map[int64]bool{})
+
+	// A failed lookup is not taken to mean the item teaches nothing; it is matched by name
+	if !c.Need(item(99, "B")) {
+		t.Error("widget whose item lookup failed should be matched by name")
+	}
+	if !c.Need(item(10, "A")) || c.Need(item(98, "A")) {
+		t.Error("widget whose item lookup worked should be matched by item")
+	}
+}
+
+func TestTeaches(t *testing.T) {
+	source := Source{Kind: "widget", Teaches: func(i wowitem.Item) bool { return i.ID() == 1 }}
+	c := NewWithData(source, map[string][]int64{"A": {5}}, map[int64]bool{})
+	if !c.Need(item(1, "A")) || c.Need(item(2, "A")) {
+		t.Error("Teaches filter not applied")
+	}
+}
+
+func TestItemOfDetail(t *testing.T) {
+	itemOf := ItemOfDetail(func(id int64) (map[string]any, error) {
+		return map[string]any{"item": map[string]any{"id": json.Number("42")}}, nil
+	})
+	if got, err := itemOf(1); err != nil || got != 42 {
+		t.Fatalf("got %d, %v", got, err)
+	}
+	itemOf = ItemOfDetail(func(id int64) (map[string]any, error) { return map[string]any{}, nil })
+	if _, err := itemOf(1); err == nil {
+		t.Fatal("expected error for missing item")
+	}
+}
@@ -69,13 +69,3 @@ func JSONInt64(value any) (int64, error) {
 
 	return n.Int64()
 }
-
-// JSONInt64Panic converts a JSON-decoded value to int64 or panics.
-func JSONInt64Panic(value any) int64 {
-	n, err := JSONInt64(value)
-	if err != nil {
-		panic(err)
-	}
-
-	return n
-}
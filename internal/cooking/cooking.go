@@ -25,17 +25,21 @@ type CookingRecipes struct {
 	neededCount map[string]int
 }
 
-func makeRecipe(r any) Recipe {
+func makeRecipe(r any) (Recipe, error) {
 	recipe := Recipe{}
 
 	href, _ := web.MsiValued(r, []string{"key", "href"}, nil)
-	recipe.href = href.(string)
+	recipe.href = common.JSONString(href)
 	name, _ := web.MsiValued(r, []string{"name"}, nil)
-	recipe.name = name.(string)
+	recipe.name = common.JSONString(name)
 	id, _ := web.MsiValued(r, []string{"id"}, nil)
-	recipe.id = common.JSONInt64Panic(id)
+	var err error
+	recipe.id, err = common.JSONInt64(id)
+	if err != nil {
+		return Recipe{}, fmt.Errorf("recipe %q id: %w", recipe.name, err)
+	}
 
-	return recipe
+	return recipe, nil
 }
 
 func getProfession(realm, alt, professionName string) (any, error) {
@@ -46,8 +50,12 @@ func getProfession(realm, alt, professionName string) (any, error) {
 	}
 
 	// Find the desired profession
-	s, _ := web.MsiValued(result, []string{"secondaries"}, nil)
-	for _, prof := range s.([]any) {
+	s, _ := web.MsiValued(result, []string{"secondaries"}, []any{})
+	secondaries, ok := s.([]any)
+	if !ok {
+		return nil, fmt.Errorf("secondaries has type %T, want []any", s)
+	}
+	for _, prof := range secondaries {
 		name, _ := web.MsiValued(prof, []string{"profession", "name"}, nil)
 		if name == professionName {
 			return prof, nil
@@ -60,8 +68,12 @@ func getProfession(realm, alt, professionName string) (any, error) {
 
 // getTier returns the desired tier (Classic, Outland, etc.)
 func getTier(prof any, tierName string) (any, error) {
-	tiers, _ := web.MsiValued(prof, []string{"tiers"}, nil)
-	for _, tier := range tiers.([]any) {
+	v, _ := web.MsiValued(prof, []string{"tiers"}, []any{})
+	tiers, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("tiers has type %T, want []any", v)
+	}
+	for _, tier := range tiers {
 		t, _ := web.MsiValued(tier, []string{"tier", "name"}, nil)
 		if t == tierName {
 			return tier, nil
@@ -83,10 +95,17 @@ func knownClassicCookingRecipes(realm, alt string) (map[int64]Recipe, error) {
 		return nil, err
 	}
 
-	kr, _ := web.MsiValued(tier, []string{"known_recipes"}, nil)
+	kr, _ := web.MsiValued(tier, []string{"known_recipes"}, []any{})
+	known, ok := kr.([]any)
+	if !ok {
+		return nil, fmt.Errorf("known_recipes has type %T, want []any", kr)
+	}
 	recipes := map[int64]Recipe{}
-	for _, k := range kr.([]any) {
-		recipe := makeRecipe(k)
+	for _, k := range known {
+		recipe, err := makeRecipe(k)
+		if err != nil {
+			return nil, err
+		}
 		if recipe.name == "Captain Rumsey's Lager" {
 			// This is a quest reward or something; won't be found in the AH
 			continue
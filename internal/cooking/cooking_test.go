@@ -9,10 +9,17 @@ import (
 )
 
 func TestMakeRecipe(t *testing.T) {
-	r := makeRecipe(map[string]any{"key": map[string]any{"href": "/recipe/1"}, "name": "Recipe One", "id": json.Number("42")})
+	r, err := makeRecipe(map[string]any{"key": map[string]any{"href": "/recipe/1"}, "name": "Recipe One", "id": json.Number("42")})
+	if err != nil {
+		t.Fatal(err)
+	}
 	if r.href != "/recipe/1" || r.name != "Recipe One" || r.id != 42 {
 		t.Fatalf("%+v", r)
 	}
+
+	if _, err := makeRecipe(map[string]any{"name": "No ID"}); err == nil {
+		t.Error("expected error for missing id")
+	}
 }
 func TestGetTier(t *testing.T) {
 	prof := map[string]any{"tiers": []any{map[string]any{"tier": map[string]any{"name": "Classic Cooking"}}, map[string]any{"tier": map[string]any{"name": "Outland Cooking"}}}}
@@ -22,6 +29,9 @@ func TestGetTier(t *testing.T) {
 	if _, err := getTier(prof, "Missing"); err == nil {
 		t.Fatal("expected error")
 	}
+	if _, err := getTier(map[string]any{"tiers": "Classic Cooking"}, "Classic Cooking"); err == nil {
+		t.Fatal("malformed tiers should be an error")
+	}
 }
 func TestKey(t *testing.T) {
 	if got := key(userconfig.Alt{Realm: "A", Name: "B"}); got != "A-B" {
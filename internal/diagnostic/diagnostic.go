@@ -0,0 +1,54 @@
+package diagnostic
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Diagnostic records web API data the run could not use. The run carries on without it,
+// and the diagnostics are reported at the end so the data can be looked into.
+type Diagnostic struct {
+	Realm  string
+	ItemID int64 // 0 if the problem is not with a single item
+	Err    error
+}
+
+func (d Diagnostic) String() string {
+	if d.ItemID == 0 {
+		return fmt.Sprintf("%s: %v", d.Realm, d.Err)
+	}
+	return fmt.Sprintf("%s: item %d: %v", d.Realm, d.ItemID, d.Err)
+}
+
+// Report returns a maintenance report listing the diagnostics by realm, then item, or "" if there are none
+func Report(diagnostics []Diagnostic) string {
+	if len(diagnostics) == 0 {
+		return ""
+	}
+
+	sorted := slices.Clone(diagnostics)
+	slices.SortStableFunc(sorted, func(a, b Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.Realm, b.Realm), cmp.Compare(a.ItemID, b.ItemID))
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Maintenance report: %d problems with web API data\n", len(sorted))
+	realm := ""
+	for i, d := range sorted {
+		if i == 0 || d.Realm != realm {
+			realm = d.Realm
+			fmt.Fprintf(&b, "\n%s\n", realm)
+		}
+		// Joined errors span several lines; keep them under their item
+		msg := strings.ReplaceAll(d.Err.Error(), "\n", "\n    ")
+		if d.ItemID == 0 {
+			fmt.Fprintf(&b, "  %s\n", msg)
+			continue
+		}
+		fmt.Fprintf(&b, "  item %d: %s  https://www.wowhead.com/item=%d\n", d.ItemID, msg, d.ItemID)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package diagnostic
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	d := Diagnostic{Realm: "Aegwynn", ItemID: 42, Err: errors.New("quantity missing")}
+	if got := d.String(); got != "Aegwynn: item 42: quantity missing" {
+		t.Errorf("got %q", got)
+	}
+
+	d = Diagnostic{Realm: "Aegwynn", Err: errors.New("failed to scan realm")}
+	if got := d.String(); got != "Aegwynn: failed to scan realm" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestReport(t *testing.T) {
+	if got := Report(nil); got != "" {
+		t.Errorf("empty report = %q", got)
+	}
+
+	report := Report([]Diagnostic{
+		{Realm: "Commodities", ItemID: 7, Err: errors.New("level missing")},
+		{Realm: "Aegwynn", ItemID: 42, Err: errors.Join(errors.New("level missing"), errors.New("is_stackable missing"))},
+		{Realm: "Aegwynn", Err: errors.New("auction 5: item.id missing")},
+	})
+
+	want := `Maintenance report: 3 problems with web API data
+
+Aegwynn
+  auction 5: item.id missing
+  item 42: level missing
+    is_stackable missing  https://www.wowhead.com/item=42
+
+Commodities
+  item 7: level missing  https://www.wowhead.com/item=7
+`
+	if report != want {
+		t.Errorf("got:\n%s\nwant:\n%s", report, want)
+	}
+	if strings.Count(report, "Aegwynn") != 1 {
+		t.Errorf("realm listed more than once:\n%s", report)
+	}
+}
@@ -0,0 +1,31 @@
+package heirloom
+
+import (
+	"github.com/erikbryant/wow/internal/collectible"
+	"github.com/erikbryant/wow/internal/wowapi"
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+type Heirloom struct {
+	*collectible.Collection
+}
+
+// source describes how to load heirlooms
+var source = collectible.Source{
+	Kind:     "heirloom",
+	Index:    wowapi.Heirlooms,
+	Owned:    wowapi.CollectionsHeirlooms,
+	OwnedKey: "heirloom",
+	ItemOf:   collectible.ItemOfDetail(wowapi.Heirloom),
+	Teaches:  func(i wowitem.Item) bool { return i.Quality() == "Heirloom" },
+}
+
+// New loads the heirlooms, keeping the item that teaches each one in itemsPath
+func New(itemsPath string) (*Heirloom, error) {
+	c, err := collectible.New(source, itemsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Heirloom{Collection: c}, nil
+}
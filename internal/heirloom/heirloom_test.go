@@ -0,0 +1,38 @@
+package heirloom
+
+import (
+	"testing"
+
+	"github.com/erikbryant/wow/internal/collectible"
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+func heirloomItem(id int64, name, quality string) wowitem.Item {
+	return wowitem.Item{XID: id, XItem: map[string]any{"name": name, "preview_item": map[string]any{"quality": map[string]any{"name": quality}}}}
+}
+
+// testSource is source with the teaching item of heirloom N being item N/100
+func testSource() collectible.Source {
+	s := source
+	s.ItemOf = func(id int64) (int64, error) { return id / 100, nil }
+	return s
+}
+
+func TestOwned(test *testing.T) {
+	h := &Heirloom{Collection: collectible.NewWithData(testSource(), map[string][]int64{"Heirloom A": {100}, "Heirloom B": {200}}, map[int64]bool{100: true})}
+	if !h.Owned(heirloomItem(1, "Heirloom A", "Heirloom")) || h.Need(heirloomItem(1, "Heirloom A", "Heirloom")) {
+		test.Error("owned heirloom")
+	}
+	if h.Owned(heirloomItem(2, "Heirloom B", "Heirloom")) || !h.Need(heirloomItem(2, "Heirloom B", "Heirloom")) {
+		test.Error("unowned heirloom should be needed")
+	}
+	if h.Need(heirloomItem(2, "Heirloom B", "Epic")) {
+		test.Error("item that is not an heirloom should not be needed")
+	}
+	if h.Need(heirloomItem(3, "Not an heirloom", "Heirloom")) {
+		test.Error("item outside the index should not be needed")
+	}
+	if h.LenIndex() != 2 || h.LenOwned() != 1 {
+		test.Errorf("LenIndex=%d LenOwned=%d", h.LenIndex(), h.LenOwned())
+	}
+}
@@ -0,0 +1,38 @@
+package mount
+
+import (
+	"github.com/erikbryant/wow/internal/collectible"
+	"github.com/erikbryant/wow/internal/wowapi"
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+type Mount struct {
+	*collectible.Collection
+}
+
+// source describes how to load mounts. The mount API does not say which item
+// teaches a mount, so mounts that share a name cannot be told apart.
+var source = collectible.Source{
+	Kind:     "mount",
+	Index:    wowapi.Mounts,
+	Owned:    wowapi.CollectionsMounts,
+	OwnedKey: "mount",
+	Teaches:  func(i wowitem.Item) bool { return i.Mount() },
+	Names:    names,
+}
+
+// names returns the mounts the item might teach. Items are usually named differently
+// from their mount ("Reins of the Azure Drake" teaches "Azure Drake"), but the spell
+// the item teaches has the mount's name.
+func names(i wowitem.Item) []string {
+	return append(i.Spells(), i.Name())
+}
+
+func New() (*Mount, error) {
+	c, err := collectible.New(source, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Mount{Collection: c}, nil
+}
@@ -0,0 +1,53 @@
+package mount
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/erikbryant/wow/internal/collectible"
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+func mountItem(id int64, name string) wowitem.Item {
+	return wowitem.Item{XID: id, XItem: map[string]any{"name": name, "item_subclass": map[string]any{"name": "Mount"}}}
+}
+func TestOwned(test *testing.T) {
+	m := &Mount{Collection: collectible.NewWithData(source, map[string][]int64{"Mount A": {6}, "Mount B": {7}, "Twin": {8, 9}}, map[int64]bool{6: true, 8: true})}
+	if !m.Owned(mountItem(1, "Mount A")) {
+		test.Error("owned mount reported false")
+	}
+	if m.Owned(mountItem(2, "Mount B")) || m.Owned(mountItem(3, "Not a mount")) {
+		test.Error("unowned/non-mount reported true")
+	}
+	if m.Owned(mountItem(4, "Twin")) || !m.Need(mountItem(4, "Twin")) {
+		test.Error("partly owned shared name should still be needed")
+	}
+	if m.LenIndex() != 4 || m.LenOwned() != 2 {
+		test.Errorf("LenIndex=%d LenOwned=%d", m.LenIndex(), m.LenOwned())
+	}
+}
+
+func TestReins(test *testing.T) {
+	m := &Mount{Collection: collectible.NewWithData(source, map[string][]int64{"Azure Drake": {246}, "Blue Drake": {247}}, map[int64]bool{247: true})}
+
+	reins := func(id int64, name, spell string) wowitem.Item {
+		i := mountItem(id, name)
+		i.XItem["preview_item"] = map[string]any{
+			"spells": []any{map[string]any{
+				"spell":       map[string]any{"id": json.Number("59567"), "name": spell},
+				"description": "Teaches you how to summon this mount.",
+			}},
+		}
+		return i
+	}
+
+	if !m.Need(reins(43952, "Reins of the Azure Drake", "Azure Drake")) {
+		test.Error("reins should teach the unowned Azure Drake")
+	}
+	if m.Need(reins(43953, "Reins of the Blue Drake", "Blue Drake")) || !m.Owned(reins(43953, "Reins of the Blue Drake", "Blue Drake")) {
+		test.Error("reins should teach the owned Blue Drake")
+	}
+	if m.Need(reins(1, "Reins of the Nothing", "Some Other Spell")) {
+		test.Error("reins teaching no known mount are not needed")
+	}
+}
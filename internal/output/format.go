@@ -0,0 +1,171 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/erikbryant/wow/internal/appearanceset"
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+// Formats are the output formats Write supports
+var Formats = []string{"table", "json", "jsonl", "csv", "markdown"}
+
+// ColumnKeys returns the key of every column, in table order.
+func ColumnKeys() []string {
+	keys := []string{}
+	for _, column := range columns {
+		keys = append(keys, column.key)
+	}
+	return keys
+}
+
+// Columns returns the columns named in a comma-separated list of keys
+// (e.g. "id,name,price"), in that order. An empty list selects every column.
+func Columns(spec string) ([]Column, error) {
+	if strings.TrimSpace(spec) == "" {
+		return columns, nil
+	}
+
+	selected := []Column{}
+
+	for key := range strings.SplitSeq(spec, ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		found := false
+		for _, column := range columns {
+			if column.key == key {
+				selected = append(selected, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column must be one of {%s} got %s", strings.Join(ColumnKeys(), ", "), key)
+		}
+	}
+
+	return selected, nil
+}
+
+// record is the selected columns of an item, keyed by column key. It encodes as a JSON
+// object with the keys in column order and typed values (e.g. the price in coppers).
+type record struct {
+	keys   []string
+	values []any
+}
+
+func newRecord(item wowitem.Item, as *appearanceset.Persistence, columns []Column) record {
+	r := record{}
+	for _, column := range columns {
+		r.keys = append(r.keys, column.key)
+		r.values = append(r.values, column.typed(item, as))
+	}
+	return r
+}
+
+// MarshalJSON encodes the record as an object, keeping the column order
+func (r record) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+// typedValues returns the typed value of each column for this item, formatted for CSV
+func typedValues(item wowitem.Item, as *appearanceset.Persistence, columns []Column) []string {
+	fields := []string{}
+
+	for _, column := range columns {
+		fields = append(fields, fmt.Sprint(column.typed(item, as)))
+	}
+
+	return fields
+}
+
+// markdownEscape keeps cell text from breaking a Markdown table row
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// Write writes the selected columns of items in the given format. The table and markdown
+// formats are for people (e.g. prices in gold); json, jsonl and csv are for programs and
+// spreadsheets (numbers, booleans and prices in coppers).
+func Write(w io.Writer, format string, items []wowitem.Item, as *appearanceset.Persistence, columns []Column) error {
+	switch format {
+	case "table":
+		tableColumns(w, items, as, columns)
+	case "json":
+		records := []record{}
+		for _, item := range items {
+			records = append(records, newRecord(item, as, columns))
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case "jsonl":
+		encoder := json.NewEncoder(w)
+		for _, item := range items {
+			if err := encoder.Encode(newRecord(item, as, columns)); err != nil {
+				return err
+			}
+		}
+	case "csv":
+		writer := csv.NewWriter(w)
+		header := []string{}
+		for _, column := range columns {
+			header = append(header, column.header)
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := writer.Write(typedValues(item, as, columns)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case "markdown":
+		header := []string{}
+		separator := []string{}
+		for _, column := range columns {
+			header = append(header, markdownEscape(column.header))
+			separator = append(separator, "---")
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+		fmt.Fprintf(w, "| %s |\n", strings.Join(separator, " | "))
+		for _, item := range items {
+			cells := []string{}
+			for _, value := range values(item, as, columns) {
+				cells = append(cells, markdownEscape(value))
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		}
+	default:
+		return fmt.Errorf("format must be one of {%s} got %s", strings.Join(Formats, ", "), format)
+	}
+
+	return nil
+}
@@ -26,7 +26,9 @@ type MerchantData struct {
 	Cosmetics []Cosmetic
 }
 
-// Lua writes item data as Lua source code.
+// Lua writes item data as Lua source code. Only realizable prices are written: the
+// addon looks items up by ID alone, so it cannot tell an upgraded item from one at
+// its base iLevel, and items whose iLevel can change are left out.
 func Lua(wi *wowitem.Persistence) string {
 	data := MerchantData{}
 
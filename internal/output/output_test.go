@@ -34,12 +34,81 @@ func TestJSON(t *testing.T) {
 func TestTable(t *testing.T) {
 	var b bytes.Buffer
 	as := appearanceset.NewEmpty(t.TempDir() + "/appearances")
-	as.Set(123, true)
+	as.Set(1, appearanceset.Set{Name: "Set", Appearances: []int64{123}})
 	Table(&b, []wowitem.Item{outputItem()}, as)
 	s := b.String()
-	for _, x := range []string{"ID", "Equips", "Stacks", "App Set", "Sell Price", "iLvl", "Class", "Quality", "Updated", "Name", "123", "Widget", "1.23.45"} {
+	for _, x := range []string{"ID", "Equips", "Stacks", "App Set", "Sell Price", "iLvl", "Class", "Quality", "Updated", "Provenance", "Name", "api", "123", "Widget", "1.23.45"} {
 		if !strings.Contains(s, x) {
 			t.Errorf("table missing %q: %s", x, s)
 		}
 	}
 }
+
+func TestColumns(t *testing.T) {
+	all, err := Columns("")
+	if err != nil || len(all) != len(ColumnKeys()) {
+		t.Fatalf("Columns(\"\") = %d columns, %v", len(all), err)
+	}
+
+	selected, err := Columns("name, ID")
+	if err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+	if len(selected) != 2 || selected[0].key != "name" || selected[1].key != "id" {
+		t.Fatalf("Columns() = %v", selected)
+	}
+
+	if _, err := Columns("id,bogus"); err == nil || !strings.Contains(err.Error(), "column must be one of") {
+		t.Fatalf("Columns() error = %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	as := appearanceset.NewEmpty(t.TempDir() + "/appearances")
+	columns, err := Columns("id,name,price")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"table", []string{"ID", "Name", "Sell Price", "123", "Widget", "1.23.45"}},
+		{"json", []string{"[\n  {\n    \"id\": 123,\n    \"name\": \"Widget\",\n    \"price\": 12345\n  }\n]\n"}},
+		{"jsonl", []string{`{"id":123,"name":"Widget","price":12345}` + "\n"}},
+		{"csv", []string{"ID,Name,Sell Price\n123,Widget,12345\n"}},
+		{"markdown", []string{"| ID | Name | Sell Price |\n| --- | --- | --- |\n| 123 | Widget | 1.23.45 |\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := Write(&b, tt.format, []wowitem.Item{outputItem()}, as, columns); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("missing %q: %s", want, b.String())
+				}
+			}
+		})
+	}
+
+	// The records keep the order given to Columns, with typed values
+	columns, err = Columns("stacks,price,ilevel,id,equips")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := Write(&b, "jsonl", []wowitem.Item{outputItem()}, as, columns); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"stacks":true,"price":12345,"ilevel":100,"id":123,"equips":false}` + "\n"; b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+
+	if err := Write(&bytes.Buffer{}, "xml", nil, as, columns); err == nil {
+		t.Error("unknown format should be an error")
+	}
+}
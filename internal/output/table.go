@@ -13,66 +13,100 @@ import (
 
 // Column contains information to retrieve each column of output
 type Column struct {
+	key    string
 	header string
 	value  func(wowitem.Item, *appearanceset.Persistence) string
+
+	// data returns the typed value for machine-readable formats (e.g. the price in coppers).
+	// Optional; columns without it use value.
+	data func(wowitem.Item, *appearanceset.Persistence) any
+}
+
+// typed returns the column's value for machine-readable formats
+func (c Column) typed(item wowitem.Item, as *appearanceset.Persistence) any {
+	if c.data == nil {
+		return c.value(item, as)
+	}
+	return c.data(item, as)
 }
 
 var columns = []Column{
 	{
+		key:    "id",
 		header: "ID",
 		value:  func(item wowitem.Item, as *appearanceset.Persistence) string { return fmt.Sprintf("%d", item.ID()) },
+		data:   func(item wowitem.Item, as *appearanceset.Persistence) any { return item.ID() },
 	},
 	{
+		key:    "equips",
 		header: "Equips",
 		value: func(item wowitem.Item, as *appearanceset.Persistence) string {
 			return fmt.Sprintf("%t", item.Equippable())
 		},
+		data: func(item wowitem.Item, as *appearanceset.Persistence) any { return item.Equippable() },
 	},
 	{
+		key:    "stacks",
 		header: "Stacks",
 		value: func(item wowitem.Item, as *appearanceset.Persistence) string {
 			return fmt.Sprintf("%t", item.Stackable())
 		},
+		data: func(item wowitem.Item, as *appearanceset.Persistence) any { return item.Stackable() },
 	},
 	{
+		key:    "appset",
 		header: "App Set",
 		value: func(item wowitem.Item, as *appearanceset.Persistence) string {
 			return fmt.Sprintf("%t", as.Contains(item.Appearances()))
 		},
+		data: func(item wowitem.Item, as *appearanceset.Persistence) any { return as.Contains(item.Appearances()) },
 	},
 	{
+		key:    "price",
 		header: "Sell Price",
 		value: func(item wowitem.Item, as *appearanceset.Persistence) string {
 			return common.Gold(item.SellPriceAdvertised())
 		},
+		data: func(item wowitem.Item, as *appearanceset.Persistence) any { return item.SellPriceAdvertised() },
 	},
 	{
+		key:    "ilevel",
 		header: "iLvl",
 		value: func(item wowitem.Item, as *appearanceset.Persistence) string {
 			return fmt.Sprintf("%d", item.ItemLevel())
 		},
+		data: func(item wowitem.Item, as *appearanceset.Persistence) any { return item.ItemLevel() },
 	},
 	{
+		key:    "class",
 		header: "Class",
 		value:  func(item wowitem.Item, as *appearanceset.Persistence) string { return item.ItemClassName() },
 	},
 	{
+		key:    "quality",
 		header: "Quality",
 		value:  func(item wowitem.Item, as *appearanceset.Persistence) string { return item.Quality() },
 	},
 	{
+		key:    "updated",
 		header: "Updated",
 		value: func(item wowitem.Item, as *appearanceset.Persistence) string {
 			return item.Updated().Format("2006-01-02")
 		},
 	},
 	{
+		key:    "provenance",
+		header: "Provenance",
+		value:  func(item wowitem.Item, as *appearanceset.Persistence) string { return item.Provenance() },
+	},
+	{
+		key:    "name",
 		header: "Name",
 		value:  func(item wowitem.Item, as *appearanceset.Persistence) string { return item.Name() },
 	},
 }
 
-func headers() (string, string) {
+func headers(columns []Column) (string, string) {
 	cols := []string{}
 	seps := []string{}
 
@@ -84,18 +118,24 @@ func headers() (string, string) {
 	return strings.Join(cols, "\t"), strings.Join(seps, "\t")
 }
 
-func row(item wowitem.Item, as *appearanceset.Persistence) string {
+// values returns the value of each column for this item
+func values(item wowitem.Item, as *appearanceset.Persistence, columns []Column) []string {
 	fields := []string{}
 
 	for _, col := range columns {
 		fields = append(fields, col.value(item, as))
 	}
 
-	return strings.Join(fields, "\t")
+	return fields
 }
 
 // Table writes items as a human-readable table.
 func Table(w io.Writer, items []wowitem.Item, as *appearanceset.Persistence) {
+	tableColumns(w, items, as, columns)
+}
+
+// tableColumns writes the given columns of items as a human-readable table.
+func tableColumns(w io.Writer, items []wowitem.Item, as *appearanceset.Persistence, columns []Column) {
 	writer := tabwriter.NewWriter(
 		w,
 		0,
@@ -105,13 +145,13 @@ func Table(w io.Writer, items []wowitem.Item, as *appearanceset.Persistence) {
 		0,
 	)
 
-	header, separator := headers()
+	header, separator := headers(columns)
 
 	fmt.Fprintln(writer, header)
 	fmt.Fprintln(writer, separator)
 
 	for _, item := range items {
-		fmt.Fprintf(writer, "%s\n", row(item, as))
+		fmt.Fprintf(writer, "%s\n", strings.Join(values(item, as, columns), "\t"))
 	}
 
 	writer.Flush()
@@ -8,15 +8,23 @@ import (
 )
 
 type Paths struct {
-	Appearances     string
-	Arbitrage       string
-	BattlePets      string
-	Items           string
-	ItemsReport     string
-	PriceCache      string
-	RecipesNeeded   string
-	Recommendations string
-	Secret          string
+	Appearances       string
+	AppearancesNeeded string
+	Arbitrage         string
+	BattlePets        string
+	HeirloomItems     string
+	ILevels           string
+	ItemChanges       string
+	Items             string
+	ItemsReport       string
+	Maintenance       string
+	PriceCache        string
+	PriceCacheStale   string
+	RecipesNeeded     string
+	Recommendations   string
+	Secret            string
+	Synthetics        string
+	ToyItems          string
 }
 
 const (
@@ -126,15 +134,23 @@ func New(rootPath string) (*Paths, error) {
 	}
 
 	p := Paths{
-		Appearances:     filepath.Join(rootPath, dataDir, "appearances"),
-		Arbitrage:       filepath.Join(rootPath, exportsDir, "arbitrageLatest"),
-		BattlePets:      filepath.Join(rootPath, reportsDir, "battlePets"),
-		Items:           filepath.Join(rootPath, dataDir, "items"),
-		ItemsReport:     filepath.Join(rootPath, reportsDir, "items"),
-		PriceCache:      filepath.Join(rootPath, exportsDir, "PriceCache.lua"),
-		RecipesNeeded:   filepath.Join(rootPath, reportsDir, "recipesNeeded"),
-		Recommendations: filepath.Join(rootPath, reportsDir, "shopping"),
-		Secret:          filepath.Join(rootPath, binDir, "secret"),
+		Appearances:       filepath.Join(rootPath, dataDir, "appearances"),
+		AppearancesNeeded: filepath.Join(rootPath, reportsDir, "appearancesNeeded"),
+		Arbitrage:         filepath.Join(rootPath, exportsDir, "arbitrageLatest"),
+		BattlePets:        filepath.Join(rootPath, reportsDir, "battlePets"),
+		HeirloomItems:     filepath.Join(rootPath, dataDir, "heirloomItems"),
+		ILevels:           filepath.Join(rootPath, dataDir, "ilevels.json"),
+		ItemChanges:       filepath.Join(rootPath, reportsDir, "itemChanges"),
+		Items:             filepath.Join(rootPath, dataDir, "items"),
+		ItemsReport:       filepath.Join(rootPath, reportsDir, "items"),
+		Maintenance:       filepath.Join(rootPath, reportsDir, "maintenance"),
+		PriceCache:        filepath.Join(rootPath, exportsDir, "PriceCache.lua"),
+		PriceCacheStale:   filepath.Join(rootPath, exportsDir, "PriceCache.lua.stale"),
+		RecipesNeeded:     filepath.Join(rootPath, reportsDir, "recipesNeeded"),
+		Recommendations:   filepath.Join(rootPath, reportsDir, "shopping"),
+		Secret:            filepath.Join(rootPath, binDir, "secret"),
+		Synthetics:        filepath.Join(rootPath, dataDir, "synthetics.json"),
+		ToyItems:          filepath.Join(rootPath, dataDir, "toyItems"),
 	}
 
 	err = create(rootPath)
@@ -56,12 +56,14 @@ func TestNewExplicitRoot(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	checks := map[string]string{"Appearances": filepath.Join(root, "data", "appearances"), "Items": filepath.Join(root, "data", "items"), "Arbitrage": filepath.Join(root, "exports", "arbitrageLatest"), "BattlePets": filepath.Join(root, "reports", "battlePets"), "PriceCache": filepath.Join(root, "exports", "PriceCache.lua"), "RecipesNeeded": filepath.Join(root, "reports", "recipesNeeded"), "Recommendations": filepath.Join(root, "reports", "shopping"), "Secret": filepath.Join(root, "bin", "secret")}
+	checks := map[string]string{"Appearances": filepath.Join(root, "data", "appearances"), "AppearancesNeeded": filepath.Join(root, "reports", "appearancesNeeded"), "Items": filepath.Join(root, "data", "items"), "Arbitrage": filepath.Join(root, "exports", "arbitrageLatest"), "BattlePets": filepath.Join(root, "reports", "battlePets"), "PriceCache": filepath.Join(root, "exports", "PriceCache.lua"), "PriceCacheStale": filepath.Join(root, "exports", "PriceCache.lua.stale"), "ItemChanges": filepath.Join(root, "reports", "itemChanges"), "Maintenance": filepath.Join(root, "reports", "maintenance"), "RecipesNeeded": filepath.Join(root, "reports", "recipesNeeded"), "Recommendations": filepath.Join(root, "reports", "shopping"), "Secret": filepath.Join(root, "bin", "secret"), "ILevels": filepath.Join(root, "data", "ilevels.json"), "Synthetics": filepath.Join(root, "data", "synthetics.json"), "HeirloomItems": filepath.Join(root, "data", "heirloomItems"), "ToyItems": filepath.Join(root, "data", "toyItems")}
 	for name, want := range checks {
 		var got string
 		switch name {
 		case "Appearances":
 			got = p.Appearances
+		case "AppearancesNeeded":
+			got = p.AppearancesNeeded
 		case "Items":
 			got = p.Items
 		case "Arbitrage":
@@ -70,12 +72,26 @@ func TestNewExplicitRoot(t *testing.T) {
 			got = p.BattlePets
 		case "PriceCache":
 			got = p.PriceCache
+		case "PriceCacheStale":
+			got = p.PriceCacheStale
+		case "ItemChanges":
+			got = p.ItemChanges
+		case "Maintenance":
+			got = p.Maintenance
 		case "RecipesNeeded":
 			got = p.RecipesNeeded
 		case "Recommendations":
 			got = p.Recommendations
 		case "Secret":
 			got = p.Secret
+		case "ILevels":
+			got = p.ILevels
+		case "Synthetics":
+			got = p.Synthetics
+		case "HeirloomItems":
+			got = p.HeirloomItems
+		case "ToyItems":
+			got = p.ToyItems
 		}
 		if got != want {
 			t.Errorf("%s=%q want %q", name, got, want)
@@ -32,6 +32,14 @@ func New[K comparable, V any](persistencePath string) *Persistence[K, V] {
 	}
 }
 
+// NewFile creates a new Persistence backed by exactly filename, whatever its suffix.
+func NewFile[K comparable, V any](filename string) *Persistence[K, V] {
+	return &Persistence[K, V]{
+		filename: filename,
+		data:     make(map[K]V),
+	}
+}
+
 // Load replaces the current data with the contents of the persistence file.
 func (p *Persistence[K, V]) Load() error {
 	p.mu.Lock()
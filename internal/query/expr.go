@@ -0,0 +1,395 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+// Expressions look like:
+//
+//	quality in (Rare,Epic) and class = "Armor" and not cosmetic
+//
+// Grammar, lowest precedence first:
+//
+//	or         := and { "or" and }
+//	and        := not { "and" not }
+//	not        := "not" not | primary
+//	primary    := "(" or ")" | comparison | flag
+//	comparison := field op value | field "in" "(" value { "," value } ")"
+//	op         := "=" | "!=" | "<" | "<=" | ">" | ">=" | "~"
+//
+// Text comparisons ignore case. '~' matches text containing the value.
+
+// field extracts a comparable value from an item; exactly one of text or number is set
+type field struct {
+	text   func(wowitem.Item) string
+	number func(wowitem.Item) int64
+}
+
+// fields are the item fields an expression can compare
+var fields = map[string]field{
+	"id":         {number: func(i wowitem.Item) int64 { return i.ID() }},
+	"name":       {text: func(i wowitem.Item) string { return i.Name() }},
+	"quality":    {text: func(i wowitem.Item) string { return i.Quality() }},
+	"class":      {text: func(i wowitem.Item) string { return i.ItemClassName() }},
+	"subclass":   {text: func(i wowitem.Item) string { return i.ItemSubclassName() }},
+	"ilevel":     {number: func(i wowitem.Item) int64 { return i.ItemLevel() }},
+	"price":      {number: func(i wowitem.Item) int64 { return i.SellPriceAdvertised() }},
+	"slot":       {text: func(i wowitem.Item) string { return i.InventoryType() }},
+	"binding":    {text: func(i wowitem.Item) string { return i.Binding() }},
+	"requires":   {text: func(i wowitem.Item) string { return i.Requirements() }},
+	"provenance": {text: func(i wowitem.Item) string { return i.Provenance() }},
+}
+
+// flags are the item properties an expression can test on their own
+var flags = map[string]func() Predicate{
+	"rare":            Rare,
+	"epic":            Epic,
+	"cosmetic":        Cosmetic,
+	"stackable":       Stackable,
+	"equippable":      Equippable,
+	"toy":             Toy,
+	"variable-ilevel": VariableItemLevel,
+	"synthetic":       Synthetic,
+}
+
+// ParseError describes where and why an expression failed to parse
+type ParseError struct {
+	Expr string
+	Pos  int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s at position %d\n  %s\n  %s^", e.Msg, e.Pos+1, e.Expr, strings.Repeat(" ", e.Pos))
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// describe returns the token as it should appear in an error message
+func (t token) describe() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lex splits an expression into tokens
+func lex(expr string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(expr)
+
+	for pos := 0; pos < len(runes); {
+		r := runes[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: pos})
+			pos++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: pos})
+			pos++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: pos})
+			pos++
+		case r == '"':
+			start := pos
+			var text strings.Builder
+			pos++
+			for pos < len(runes) && runes[pos] != '"' {
+				if runes[pos] == '\\' && pos+1 < len(runes) {
+					pos++
+				}
+				text.WriteRune(runes[pos])
+				pos++
+			}
+			if pos >= len(runes) {
+				return nil, &ParseError{Expr: expr, Pos: start, Msg: "unterminated string"}
+			}
+			pos++
+			tokens = append(tokens, token{kind: tokString, text: text.String(), pos: start})
+		case strings.ContainsRune("=!<>~", r):
+			start := pos
+			pos++
+			if pos < len(runes) && runes[pos] == '=' && r != '=' && r != '~' {
+				pos++
+			}
+			op := string(runes[start:pos])
+			if op == "!" {
+				return nil, &ParseError{Expr: expr, Pos: start, Msg: `expected "!="`}
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: start})
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-':
+			start := pos
+			for pos < len(runes) && (unicode.IsLetter(runes[pos]) || unicode.IsDigit(runes[pos]) || runes[pos] == '_' || runes[pos] == '-') {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: string(runes[start:pos]), pos: start})
+		default:
+			return nil, &ParseError{Expr: expr, Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(runes)}), nil
+}
+
+type parser struct {
+	expr   string
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokEOF {
+		p.next++
+	}
+	return t
+}
+
+// keyword returns true, and consumes the token, if the next token is this keyword
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokWord && strings.EqualFold(t.text, word) {
+		p.advance()
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, a ...any) error {
+	return &ParseError{Expr: p.expr, Pos: t.pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+func (p *parser) parseOr() (Predicate, error) {
+	predicates := []Predicate{}
+
+	for {
+		predicate, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+
+		if !p.keyword("or") {
+			break
+		}
+	}
+
+	if len(predicates) == 1 {
+		return predicates[0], nil
+	}
+	return Or(predicates...), nil
+}
+
+func (p *parser) parseAnd() (Predicate, error) {
+	predicates := []Predicate{}
+
+	for {
+		predicate, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+
+		if !p.keyword("and") {
+			break
+		}
+	}
+
+	if len(predicates) == 1 {
+		return predicates[0], nil
+	}
+	return And(predicates...), nil
+}
+
+func (p *parser) parseNot() (Predicate, error) {
+	if p.keyword("not") {
+		predicate, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Not(predicate), nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Predicate, error) {
+	t := p.advance()
+
+	switch t.kind {
+	case tokLParen:
+		predicate, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected \")\", got %s", closing.describe())
+		}
+		return predicate, nil
+	case tokWord:
+		name := strings.ToLower(t.text)
+
+		if f, ok := fields[name]; ok {
+			return p.parseComparison(t, f)
+		}
+		if flag, ok := flags[name]; ok {
+			return flag(), nil
+		}
+		return nil, p.errorf(t, "unknown field %q, want one of %s", t.text, strings.Join(names(), ", "))
+	default:
+		return nil, p.errorf(t, "expected a field, got %s", t.describe())
+	}
+}
+
+// parseComparison parses the operator and value(s) that follow a field name
+func (p *parser) parseComparison(name token, f field) (Predicate, error) {
+	if p.keyword("in") {
+		if open := p.advance(); open.kind != tokLParen {
+			return nil, p.errorf(open, "expected \"(\" after in, got %s", open.describe())
+		}
+
+		predicates := []Predicate{}
+		for {
+			predicate, err := p.parseValue(name, f, "=")
+			if err != nil {
+				return nil, err
+			}
+			predicates = append(predicates, predicate)
+
+			t := p.advance()
+			if t.kind == tokRParen {
+				break
+			}
+			if t.kind != tokComma {
+				return nil, p.errorf(t, "expected \",\" or \")\", got %s", t.describe())
+			}
+		}
+
+		return Or(predicates...), nil
+	}
+
+	op := p.advance()
+	if op.kind != tokOp {
+		return nil, p.errorf(op, "expected an operator after %s, got %s", name.text, op.describe())
+	}
+
+	return p.parseValue(name, f, op.text)
+}
+
+// parseValue parses a single value and returns the predicate comparing the field to it
+func (p *parser) parseValue(name token, f field, op string) (Predicate, error) {
+	t := p.advance()
+	if t.kind != tokWord && t.kind != tokString {
+		return nil, p.errorf(t, "expected a value, got %s", t.describe())
+	}
+
+	if f.number != nil {
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf(t, "%s needs a number, got %s", name.text, t.describe())
+		}
+		predicate, ok := compareNumber(f.number, op, n)
+		if !ok {
+			return nil, p.errorf(t, "operator %q not valid for number field %s", op, name.text)
+		}
+		return predicate, nil
+	}
+
+	value := strings.ToLower(t.text)
+	switch op {
+	case "=":
+		return func(i wowitem.Item) bool { return strings.ToLower(f.text(i)) == value }, nil
+	case "!=":
+		return func(i wowitem.Item) bool { return strings.ToLower(f.text(i)) != value }, nil
+	case "~":
+		return func(i wowitem.Item) bool { return strings.Contains(strings.ToLower(f.text(i)), value) }, nil
+	default:
+		return nil, p.errorf(t, "operator %q not valid for text field %s", op, name.text)
+	}
+}
+
+// compareNumber returns a predicate comparing a numeric field to n, or false if op is not a numeric operator
+func compareNumber(value func(wowitem.Item) int64, op string, n int64) (Predicate, bool) {
+	switch op {
+	case "=":
+		return func(i wowitem.Item) bool { return value(i) == n }, true
+	case "!=":
+		return func(i wowitem.Item) bool { return value(i) != n }, true
+	case "<":
+		return func(i wowitem.Item) bool { return value(i) < n }, true
+	case "<=":
+		return func(i wowitem.Item) bool { return value(i) <= n }, true
+	case ">":
+		return func(i wowitem.Item) bool { return value(i) > n }, true
+	case ">=":
+		return func(i wowitem.Item) bool { return value(i) >= n }, true
+	default:
+		return nil, false
+	}
+}
+
+// names returns the sorted names of all fields and flags
+func names() []string {
+	all := []string{}
+	for name := range fields {
+		all = append(all, name)
+	}
+	for name := range flags {
+		all = append(all, name)
+	}
+	slices.Sort(all)
+	return all
+}
+
+// Parse converts an expression into a predicate.
+func Parse(expr string) (Predicate, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{expr: expr, tokens: tokens}
+
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "empty expression")
+	}
+
+	predicate, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "expected and, or or end of expression, got %s", t.describe())
+	}
+
+	return predicate, nil
+}
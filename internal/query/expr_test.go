@@ -0,0 +1,109 @@
+package query
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+func exprItems() []wowitem.Item {
+	return []wowitem.Item{
+		qi(1, "Alpha Helm", "Rare", "Armor", 100),
+		qi(2, "Beta Blade", "Epic", "Weapon", 200),
+		qi(3, "Gamma Robe", "Common", "Armor", 150),
+		qi(4, "Delta Cloak", "Epic", "Armor", 1),
+	}
+}
+
+func ids(items []wowitem.Item) []int64 {
+	result := []int64{}
+	for _, item := range items {
+		result = append(result, item.ID())
+	}
+	return result
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []int64
+	}{
+		{`quality in (Rare,Epic) and class = "Armor" and not cosmetic`, []int64{1}},
+		{`rare or epic`, []int64{1, 2, 4}},
+		{`not (rare or epic)`, []int64{3}},
+		{`class = armor and ilevel >= 100`, []int64{1, 3}},
+		{`ilevel < 150 or id = 2`, []int64{1, 2, 4}},
+		{`name ~ "a h" or name ~ robe`, []int64{1, 3}},
+		{`quality != Common AND NOT class = Weapon`, []int64{1, 4}},
+		{`epic and ilevel > 1 or id = 3`, []int64{2, 3}},
+		{`not not cosmetic`, []int64{4}},
+		{`synthetic or provenance = api and id = 3`, []int64{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			predicate, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got := ids(Find(exprItems(), predicate))
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Find() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{``, 0, "empty expression"},
+		{`rare and`, 8, "expected a field"},
+		{`colour = red`, 0, `unknown field "colour"`},
+		{`(rare or epic`, 13, `expected ")"`},
+		{`ilevel > high`, 9, "ilevel needs a number"},
+		{`name > "x"`, 7, `operator ">" not valid`},
+		{`name = "x`, 7, "unterminated string"},
+		{`quality in Rare`, 11, `expected "(" after in`},
+		{`quality in (Rare Epic)`, 17, `expected "," or ")"`},
+		{`rare epic`, 5, "expected and, or or end of expression"},
+		{`class Armor`, 6, "expected an operator"},
+		{`id = 1 & rare`, 7, "unexpected character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse() error = %v, want *ParseError", err)
+			}
+			if parseErr.Pos != tt.pos || !strings.Contains(parseErr.Msg, tt.msg) {
+				t.Fatalf("Parse() error at %d %q, want at %d %q", parseErr.Pos, parseErr.Msg, tt.pos, tt.msg)
+			}
+			if !strings.Contains(err.Error(), strings.Repeat(" ", tt.pos)+"^") {
+				t.Fatalf("Error() does not point at position: %s", err)
+			}
+		})
+	}
+}
+
+func TestParseItemFields(t *testing.T) {
+	items := exprItems()
+	items[0].XItem["preview_item"] = map[string]any{"quality": map[string]any{"name": "Rare"}, "sell_price": map[string]any{"value": jsonNumber(5000)}}
+	items[1].XItem["is_stackable"] = true
+
+	predicate, err := Parse(`price >= 1000 or stackable`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := ids(Find(items, predicate)); !slices.Equal(got, []int64{1, 2}) {
+		t.Fatalf("Find() = %v, want [1 2]", got)
+	}
+}
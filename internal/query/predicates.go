@@ -2,6 +2,7 @@ package query
 
 import (
 	"strings"
+	"time"
 
 	"github.com/erikbryant/wow/internal/appearanceset"
 	"github.com/erikbryant/wow/internal/wowitem"
@@ -66,3 +67,96 @@ func ItemID(itemID int64) Predicate {
 		return item.ID() == itemID
 	}
 }
+
+// Cosmetic returns true for cosmetic items.
+func Cosmetic() Predicate {
+	return func(item wowitem.Item) bool {
+		return item.Cosmetic()
+	}
+}
+
+// Synthetic returns true for synthetic items, made up rather than fetched from the web API.
+func Synthetic() Predicate {
+	return func(item wowitem.Item) bool {
+		return item.Synthetic()
+	}
+}
+
+// SellPriceAtLeast returns true for items that sell to a vendor for at least price coppers.
+func SellPriceAtLeast(price int64) Predicate {
+	return func(item wowitem.Item) bool {
+		return item.SellPriceAdvertised() >= price
+	}
+}
+
+// SellPriceAtMost returns true for items that sell to a vendor for at most price coppers.
+func SellPriceAtMost(price int64) Predicate {
+	return func(item wowitem.Item) bool {
+		return item.SellPriceAdvertised() <= price
+	}
+}
+
+// ItemSubclass returns true for items of a specific subclass.
+func ItemSubclass(subclass string) Predicate {
+	return func(item wowitem.Item) bool {
+		return item.ItemSubclassName() == subclass
+	}
+}
+
+// InventoryType returns true for items that equip to a specific slot.
+func InventoryType(inventoryType string) Predicate {
+	return func(item wowitem.Item) bool {
+		return item.InventoryType() == inventoryType
+	}
+}
+
+// Binding returns true for items with a specific binding.
+func Binding(binding string) Predicate {
+	return func(item wowitem.Item) bool {
+		return item.Binding() == binding
+	}
+}
+
+// Stackable returns true for items that stack in the inventory.
+func Stackable() Predicate {
+	return func(item wowitem.Item) bool {
+		return item.Stackable()
+	}
+}
+
+// Equippable returns true for items that can be equipped.
+func Equippable() Predicate {
+	return func(item wowitem.Item) bool {
+		return item.Equippable()
+	}
+}
+
+// Toy returns true for toys.
+func Toy() Predicate {
+	return func(item wowitem.Item) bool {
+		return item.Toy()
+	}
+}
+
+// VariableItemLevel returns true for items whose item level can change.
+func VariableItemLevel() Predicate {
+	return func(item wowitem.Item) bool {
+		return item.VariableItemLevel()
+	}
+}
+
+// RequiresProfession returns true if the item's skill requirement contains text.
+func RequiresProfession(text string) Predicate {
+	text = strings.ToLower(text)
+	return func(item wowitem.Item) bool {
+		requirements := item.Requirements()
+		return requirements != "" && strings.Contains(strings.ToLower(requirements), text)
+	}
+}
+
+// Stale returns true for items last updated longer ago than age.
+func Stale(age time.Duration) Predicate {
+	return func(item wowitem.Item) bool {
+		return item.Stale(age)
+	}
+}
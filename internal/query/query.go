@@ -26,3 +26,34 @@ func Find(items []wowitem.Item, predicates ...Predicate) []wowitem.Item {
 
 	return results
 }
+
+// And returns true if every predicate is true.
+func And(predicates ...Predicate) Predicate {
+	return func(item wowitem.Item) bool {
+		for _, predicate := range predicates {
+			if !predicate(item) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns true if any predicate is true.
+func Or(predicates ...Predicate) Predicate {
+	return func(item wowitem.Item) bool {
+		for _, predicate := range predicates {
+			if predicate(item) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns the inverse of a predicate.
+func Not(predicate Predicate) Predicate {
+	return func(item wowitem.Item) bool {
+		return !predicate(item)
+	}
+}
@@ -2,8 +2,11 @@ package query
 
 import (
 	"encoding/json"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/erikbryant/wow/internal/wowitem"
 )
@@ -48,3 +51,98 @@ func TestSort(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestItemPredicates(t *testing.T) {
+	sword := qi(1, "Sword", "Rare", "Weapon", 100)
+	sword.XItem["item_subclass"] = map[string]any{"name": "Swords"}
+	sword.XItem["inventory_type"] = map[string]any{"type": "WEAPON"}
+	sword.XItem["is_equippable"] = true
+	sword.XItem["preview_item"] = map[string]any{
+		"quality":      map[string]any{"name": "Rare"},
+		"binding":      map[string]any{"type": "ON_EQUIP"},
+		"sell_price":   map[string]any{"value": jsonNumber(5000)},
+		"requirements": map[string]any{"skill": map[string]any{"display_string": "Requires Blacksmithing (75)"}},
+	}
+	sword.XUpdated = time.Now().Add(-48 * time.Hour)
+
+	herb := qi(2, "Herb", "Common", "Tradeskill", 1)
+	herb.XItem["is_stackable"] = true
+	herb.XItem["preview_item"] = map[string]any{"toy": "Toy"}
+	herb.XUpdated = time.Now()
+
+	items := []wowitem.Item{sword, herb}
+
+	tests := []struct {
+		name      string
+		predicate Predicate
+		want      int64
+	}{
+		{"price at least", SellPriceAtLeast(1000), 1},
+		{"price at most", SellPriceAtMost(1000), 2},
+		{"subclass", ItemSubclass("Swords"), 1},
+		{"inventory type", InventoryType("WEAPON"), 1},
+		{"binding", Binding("ON_EQUIP"), 1},
+		{"stackable", Stackable(), 2},
+		{"equippable", Equippable(), 1},
+		{"toy", Toy(), 2},
+		{"variable ilevel", VariableItemLevel(), 1},
+		{"requires profession", RequiresProfession("blacksmithing"), 1},
+		{"stale", Stale(24 * time.Hour), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Find(items, tt.predicate)
+			if len(got) != 1 || got[0].ID() != tt.want {
+				t.Fatalf("Find() = %v, want item %d", got, tt.want)
+			}
+		})
+	}
+
+	if len(Find(items, RequiresProfession("Tailoring"))) != 0 {
+		t.Error("requirement should not match another profession")
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	items := []wowitem.Item{
+		qi(4, "Delta", "Epic", "Weapon", 50),
+		qi(1, "Alpha", "Common", "Armor", 100),
+		qi(3, "Charlie", "Rare", "Armor", 300),
+		qi(2, "Bravo", "Rare", "Weapon", 300),
+	}
+
+	tests := []struct {
+		spec string
+		want []int64
+	}{
+		{"id", []int64{1, 2, 3, 4}},
+		{"-id", []int64{4, 3, 2, 1}},
+		{"class,-ilevel", []int64{3, 1, 2, 4}},
+		{"-ilevel", []int64{2, 3, 1, 4}},
+		{"quality, name", []int64{1, 2, 3, 4}},
+		{"-quality", []int64{4, 2, 3, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			compare, err := ParseSort(tt.spec, nil)
+			if err != nil {
+				t.Fatalf("ParseSort() error = %v", err)
+			}
+			sorted := slices.Clone(items)
+			SortBy(sorted, compare)
+			got := []int64{}
+			for _, item := range sorted {
+				got = append(got, item.ID())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("SortBy(%s) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseSort("id,bogus", nil); err == nil || !strings.Contains(err.Error(), "sort order must be one of") {
+		t.Fatalf("ParseSort() error = %v", err)
+	}
+}
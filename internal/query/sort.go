@@ -1,14 +1,22 @@
 package query
 
 import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
 	"sort"
+	"strings"
 
+	"github.com/erikbryant/wow/internal/appearanceset"
+	"github.com/erikbryant/wow/internal/common"
 	"github.com/erikbryant/wow/internal/wowitem"
 )
 
 // Sort sorts items in place using the supplied comparison function.
+// Items that compare equal keep their relative order.
 func Sort(items []wowitem.Item, less func(a, b wowitem.Item) bool) {
-	sort.Slice(items, func(i, j int) bool {
+	sort.SliceStable(items, func(i, j int) bool {
 		return less(items[i], items[j])
 	})
 }
@@ -22,3 +30,84 @@ func ByName(a, b wowitem.Item) bool {
 func ByID(a, b wowitem.Item) bool {
 	return a.ID() < b.ID()
 }
+
+// Compare returns -1, 0 or 1 depending on how a and b order.
+type Compare func(a, b wowitem.Item) int
+
+// qualityRank orders qualities from poor to artifact, with no quality first
+func qualityRank(item wowitem.Item) int64 {
+	if item.Quality() == "" {
+		return -1
+	}
+	return common.QualityID(item.Quality())
+}
+
+// compareBool orders false before true
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// sortKeys returns the sort keys, one for each table column
+func sortKeys(as *appearanceset.Persistence) map[string]Compare {
+	return map[string]Compare{
+		"id":         func(a, b wowitem.Item) int { return cmp.Compare(a.ID(), b.ID()) },
+		"equips":     func(a, b wowitem.Item) int { return compareBool(a.Equippable(), b.Equippable()) },
+		"stacks":     func(a, b wowitem.Item) int { return compareBool(a.Stackable(), b.Stackable()) },
+		"price":      func(a, b wowitem.Item) int { return cmp.Compare(a.SellPriceAdvertised(), b.SellPriceAdvertised()) },
+		"ilevel":     func(a, b wowitem.Item) int { return cmp.Compare(a.ItemLevel(), b.ItemLevel()) },
+		"class":      func(a, b wowitem.Item) int { return cmp.Compare(a.ItemClassName(), b.ItemClassName()) },
+		"quality":    func(a, b wowitem.Item) int { return cmp.Compare(qualityRank(a), qualityRank(b)) },
+		"updated":    func(a, b wowitem.Item) int { return a.Updated().Compare(b.Updated()) },
+		"provenance": func(a, b wowitem.Item) int { return cmp.Compare(a.Provenance(), b.Provenance()) },
+		"name":       func(a, b wowitem.Item) int { return cmp.Compare(a.Name(), b.Name()) },
+		"appset": func(a, b wowitem.Item) int {
+			return compareBool(as.Contains(a.Appearances()), as.Contains(b.Appearances()))
+		},
+	}
+}
+
+// ParseSort converts a comma-separated list of sort keys (e.g. "class,-price") into
+// a comparison. A leading '-' reverses that key. Ties are broken by item ID so the
+// order is reproducible.
+func ParseSort(spec string, as *appearanceset.Persistence) (Compare, error) {
+	keys := sortKeys(as)
+	compares := []Compare{}
+
+	for key := range strings.SplitSeq(spec, ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		reverse := strings.HasPrefix(key, "-")
+		key = strings.TrimPrefix(key, "-")
+
+		compare, ok := keys[key]
+		if !ok {
+			return nil, fmt.Errorf("sort order must be one of {%s} got %s", strings.Join(slices.Sorted(maps.Keys(keys)), ", "), key)
+		}
+		if reverse {
+			forward := compare
+			compare = func(a, b wowitem.Item) int { return forward(b, a) }
+		}
+		compares = append(compares, compare)
+	}
+	compares = append(compares, keys["id"])
+
+	return func(a, b wowitem.Item) int {
+		for _, compare := range compares {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}, nil
+}
+
+// SortBy stably sorts items in place using the supplied comparison.
+func SortBy(items []wowitem.Item, compare Compare) {
+	slices.SortStableFunc(items, compare)
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/erikbryant/wow/internal/application"
 	"github.com/erikbryant/wow/internal/auction"
 	"github.com/erikbryant/wow/internal/battlepet"
+	"github.com/erikbryant/wow/internal/collectible"
 	"github.com/erikbryant/wow/internal/common"
+	"github.com/erikbryant/wow/internal/diagnostic"
 	"github.com/erikbryant/wow/internal/output"
 	"github.com/erikbryant/wow/internal/query"
 	"github.com/erikbryant/wow/internal/wowitem"
@@ -19,16 +21,22 @@ import (
 
 // Recommendations holds all recommended auctions for a single realm
 type Recommendations struct {
-	AppearanceBargains []string
-	ArbitrageLogs      []string
-	ArbitrageProfit    int64
-	Arbitrages         []string
-	Bargains           []string
-	NumUniqueItems     int
-	PetNeededBargains  []string
-	PetResellBargains  []string
-	Realm              string
-	Err                error
+	AppearanceBargains    []string
+	AppearanceSetBargains []string
+	ArbitrageLogs         []string
+	ArbitrageProfit       int64
+	Arbitrages            []string
+	Bargains              []string
+	BidBargains           []string
+	EstimatedArbitrages   []string
+	NumUniqueItems        int
+	PetNeededBargains     []string
+	PetResellBargains     []string
+	Realm                 string
+	Err                   error
+
+	// Diagnostics holds the auctions and items skipped because their web API data was unusable
+	Diagnostics []diagnostic.Diagnostic
 }
 
 // petSpellNeeded returns true if we do not have this pet and it is a good price
@@ -42,6 +50,24 @@ func petNeeded(petAuction auction.Auction, app *application.App) bool {
 	return !app.BattlePets.Owned(petAuction.Pet.SpeciesID) && petAuction.Buyout <= app.ShoppingConfig.BattlePetPriceUnownedMax
 }
 
+// petSpellLabel returns the shopping list entry for a pet summoning spell
+func petSpellLabel(i wowitem.Item, petID int64, app *application.App) string {
+	if !app.BattlePets.Cageable(petID) {
+		// Once learned it can never be caged and sold on
+		return fmt.Sprintf("%s %s (spell, uncageable)", app.BattlePets.Name(petID), i.Quality())
+	}
+	return fmt.Sprintf("%s %s (spell)", app.BattlePets.Name(petID), i.Quality())
+}
+
+// petResellLabel returns the shopping list entry for a pet to resell, warning if I could not learn it
+func petResellLabel(petAuction auction.Auction, app *application.App) string {
+	speciesID := petAuction.Pet.SpeciesID
+	if app.BattlePets.AtCap(speciesID) {
+		return fmt.Sprintf("%s (AT CAP %d/%d)", app.BattlePets.Name(speciesID), app.BattlePets.Count(speciesID), battlepet.MaxPerSpecies)
+	}
+	return app.BattlePets.Name(speciesID)
+}
+
 // petResellBargain returns true if pet is likely to resell at a profit
 func petResellBargain(petAuction auction.Auction, app *application.App) bool {
 	_, ok := app.ShoppingConfig.SkipPets[petAuction.Pet.SpeciesID]
@@ -60,22 +86,44 @@ func petResellBargain(petAuction auction.Auction, app *application.App) bool {
 	return true
 }
 
-// missingProfessionTool returns true if we do not have an entry for this tool in wowitem/ilevel.go
-func missingProfessionTool(i wowitem.Item, app *application.App) bool {
-	if i.SellPriceRealizable() <= app.ShoppingConfig.ArbitrageProfitMin {
-		// Not enough profit to make it worth the WoW runtime it takes to scan the AH
+// unresolvedProfessionTool returns true the first time this tool is listed with a bonus list that has no known iLevel
+func unresolvedProfessionTool(i wowitem.Item, auc auction.Auction, app *application.App) bool {
+	if i.ItemClassName() != "Profession" {
 		return false
 	}
-	return i.ItemClassName() == "Profession" && !wowitem.Known(i.ID())
+	if !app.ILevels.Observe(i, auc.BonusLists) {
+		return false
+	}
+	// Not enough profit to make it worth the WoW runtime it takes to scan the AH
+	return i.SellPriceRealizable() > app.ShoppingConfig.ArbitrageProfitMin
+}
+
+// sellPrice returns what a vendor pays for the item in this auction, at the iLevel its bonuses give it
+func sellPrice(i wowitem.Item, auc auction.Auction, app *application.App) wowitem.SellPrice {
+	return i.SellPriceListed(auc.BonusLists, app.ILevels.Effective(i, auc.BonusLists))
 }
 
 // isArbitrage returns true if the item for auction sells to a vendor for more than the auction price
 func isArbitrage(i wowitem.Item, auc auction.Auction, app *application.App) (int64, bool) {
-	if auc.Buyout >= i.SellPriceRealizable() {
+	return arbitrageProfit(sellPrice(i, auc, app).Realizable(), auc, app)
+}
+
+// isEstimatedArbitrage returns true if the item would be an arbitrage at an estimated, unvalidated sell price
+func isEstimatedArbitrage(i wowitem.Item, auc auction.Auction, app *application.App) (int64, bool) {
+	price := sellPrice(i, auc, app)
+	if price.Confidence != wowitem.ConfidenceEstimated {
+		return 0, false
+	}
+	return arbitrageProfit(price.Value, auc, app)
+}
+
+// arbitrageProfit returns the profit from buying the auction and selling it to a vendor for price
+func arbitrageProfit(price int64, auc auction.Auction, app *application.App) (int64, bool) {
+	if auc.Buyout >= price {
 		// Not enough profit to make it worth the WoW runtime it takes to scan the AH
 		return 0, false
 	}
-	profit := (i.SellPriceRealizable() - auc.Buyout) * auc.Quantity
+	profit := (price - auc.Buyout) * auc.Quantity
 	if profit < app.ShoppingConfig.ArbitrageProfitMin {
 		// Not enough profit to make it worth the WoW runtime it takes to scan the AH
 		return 0, false
@@ -83,10 +131,42 @@ func isArbitrage(i wowitem.Item, auc auction.Auction, app *application.App) (int
 	return profit, true
 }
 
+// bidBargain returns the profit if the item's current bid is below its vendor price and
+// the auction ends soon, so is unlikely to be outbid
+func bidBargain(i wowitem.Item, auc auction.Auction, app *application.App) (int64, bool) {
+	if app.ShoppingConfig.BidExpiryMax <= 0 || !auc.ExpiresWithin(app.ShoppingConfig.BidExpiryMax) {
+		return 0, false
+	}
+	price := sellPrice(i, auc, app).Realizable()
+	if auc.Bid >= price {
+		return 0, false
+	}
+	profit := (price - auc.Bid) * auc.Quantity
+	if profit < app.ShoppingConfig.ArbitrageProfitMin {
+		// Not enough profit to make it worth the WoW runtime it takes to scan the AH
+		return 0, false
+	}
+	return profit, true
+}
+
+// collectibleBargain returns true if the item teaches a collectible we need, and it is at or below maxPrice
+func collectibleBargain(c collectible.Collectible, i wowitem.Item, auc auction.Auction, maxPrice int64) bool {
+	return auc.Buyout <= maxPrice && c.Need(i)
+}
+
 // toyBargain returns true if we need this toy, and it is at or below our price
 func toyBargain(i wowitem.Item, auc auction.Auction, app *application.App) bool {
-	// Bargains on toys
-	return i.Toy() && !app.Toys.Owned(i) && auc.Buyout <= app.ShoppingConfig.ToyPriceMax
+	return collectibleBargain(app.Toys, i, auc, app.ShoppingConfig.ToyPriceMax)
+}
+
+// mountBargain returns true if we need this mount, and it is at or below our price
+func mountBargain(i wowitem.Item, auc auction.Auction, app *application.App) bool {
+	return collectibleBargain(app.Mounts, i, auc, app.ShoppingConfig.MountPriceMax)
+}
+
+// heirloomBargain returns true if we need this heirloom, and it is at or below our price
+func heirloomBargain(i wowitem.Item, auc auction.Auction, app *application.App) bool {
+	return collectibleBargain(app.Heirlooms, i, auc, app.ShoppingConfig.HeirloomPriceMax)
 }
 
 // usefulGoodsBargain returns true if the item for auction is at or below our price
@@ -97,30 +177,55 @@ func usefulGoodsBargain(i wowitem.Item, auc auction.Auction, app *application.Ap
 
 // appearanceBargain returns true if the item for auction provides an appearance we need at a good price
 func appearanceBargain(i wowitem.Item, auc auction.Auction, app *application.App) bool {
-	return auc.Buyout <= app.ShoppingConfig.AppearancePriceMax && app.Appearances.Need(i.Appearances())
+	return auc.Buyout <= app.ShoppingConfig.AppearancePriceMax && app.Appearances.Need(i)
 }
 
 // appearanceSetBargain returns true if the item for auction provides an appearance (that is in a set) we need at a good price
 func appearanceSetBargain(i wowitem.Item, auc auction.Auction, app *application.App) bool {
-	return auc.Buyout <= app.ShoppingConfig.AppearancePriceInSetMax && app.AppearanceSet.Contains(i.Appearances()) && app.Appearances.Need(i.Appearances())
+	return auc.Buyout <= app.ShoppingConfig.AppearancePriceInSetMax && app.AppearanceSet.Contains(i.Appearances()) && app.Appearances.Need(i)
+}
+
+// appearanceSetLabel returns the shopping list entry for an item in an appearance set. It leads with the
+// number of appearances the closest set still needs so nearly finished sets sort to the top.
+func appearanceSetLabel(i wowitem.Item, app *application.App) string {
+	c, ok := app.AppearanceSet.ClosestToComplete(i.Appearances(), app.Appearances.Owned)
+	if !ok {
+		return i.Name() + " ---"
+	}
+	return fmt.Sprintf("%2d to go  %s --- %s (%d/%d)", c.Missing(), i.Name(), c.Name, c.Owned, c.Total)
+}
+
+// getItem returns the item for itemID, recording a diagnostic if it cannot be used
+func (r *Recommendations) getItem(itemID int64, app *application.App) (wowitem.Item, bool) {
+	i, err := app.WowItem.Get(itemID)
+	if err == nil {
+		// Items persisted before they were validated on download may still be malformed
+		err = i.Validate()
+	}
+	if err != nil {
+		r.Diagnostics = append(r.Diagnostics, diagnostic.Diagnostic{Realm: r.Realm, ItemID: itemID, Err: err})
+		return wowitem.Item{}, false
+	}
+
+	return i, true
 }
 
 // iterateAuctions iterates over a single auction house, checking each auction for recommendation
 func (r *Recommendations) iterateAuctions(auctions map[int64][]auction.Auction, commodities bool, app *application.App) {
 	for itemID, itemAuctions := range auctions {
-		i, err := app.WowItem.Get(itemID)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error getting itemID %6d, commodities=%5t: %v  https://www.wowhead.com/item=%d\n", itemID, commodities, err, itemID)
+		i, ok := r.getItem(itemID, app)
+		if !ok {
 			continue
 		}
 
-		if missingProfessionTool(i, app) {
-			// We have not seen this profession tool yet; add it to wowitem/ilevel.go
-			fmt.Fprintf(os.Stderr, "%d: {}, // %s iLvl: %d\n", i.ID(), i.Name(), i.ItemLevel())
-		}
-
 		for _, auc := range itemAuctions {
 
+			// Learn the iLevels profession tools are sold at before they are needed below
+			if !commodities && unresolvedProfessionTool(i, auc, app) {
+				// Add how much the bonus ID that sets the tool's quality adds to its iLevel to the iLevels data file
+				fmt.Fprintf(os.Stderr, "*** %d %s listed with bonus list %v; none of its bonus IDs has a known iLevel adjustment\n", i.ID(), i.Name(), auc.BonusLists)
+			}
+
 			// ----- Business logic applicable to commodities and regular auctions -----
 
 			profit, ok := isArbitrage(i, auc, app)
@@ -129,11 +234,16 @@ func (r *Recommendations) iterateAuctions(auctions map[int64][]auction.Auction,
 				r.Arbitrages = append(r.Arbitrages, str)
 				r.ArbitrageProfit += profit
 				if !commodities {
-					for _, iLevel := range wowitem.ILevels(i.ID()) {
+					for _, iLevel := range app.ILevels.ILevels(i.ID()) {
 						record := fmt.Sprintf("    {%d, %d}, -- %s", i.ID(), iLevel, i.Name())
 						r.ArbitrageLogs = append(r.ArbitrageLogs, record)
 					}
 				}
+			} else if profit, ok := isEstimatedArbitrage(i, auc, app); ok {
+				// Not trusted enough to act on; listed so the price can be checked in game
+				price := sellPrice(i, auc, app)
+				str := fmt.Sprintf("%s   %s  (%s, context %d, bonuses %v)", i.Name(), common.Gold(profit), price.Rule, auc.Context, auc.BonusLists)
+				r.EstimatedArbitrages = append(r.EstimatedArbitrages, str)
 			}
 
 			if toyBargain(i, auc, app) || usefulGoodsBargain(i, auc, app) {
@@ -149,7 +259,7 @@ func (r *Recommendations) iterateAuctions(auctions map[int64][]auction.Auction,
 
 			if i.ID() == battlepet.PetCageItemID {
 				if petResellBargain(auc, app) {
-					r.PetResellBargains = append(r.PetResellBargains, app.BattlePets.Name(auc.Pet.SpeciesID))
+					r.PetResellBargains = append(r.PetResellBargains, petResellLabel(auc, app))
 				}
 				if petNeeded(auc, app) {
 					r.PetNeededBargains = append(r.PetNeededBargains, app.BattlePets.Name(auc.Pet.SpeciesID))
@@ -159,17 +269,16 @@ func (r *Recommendations) iterateAuctions(auctions map[int64][]auction.Auction,
 
 			if petSpellNeeded(i, auc, app) {
 				petID, _ := app.BattlePets.PetSpell(i)
-				pet := fmt.Sprintf("%s %s (spell)", app.BattlePets.Name(petID), i.Quality())
-				r.PetNeededBargains = append(r.PetNeededBargains, pet)
+				r.PetNeededBargains = append(r.PetNeededBargains, petSpellLabel(i, petID, app))
 			}
 
-			if toyBargain(i, auc, app) {
+			if toyBargain(i, auc, app) || mountBargain(i, auc, app) || heirloomBargain(i, auc, app) {
 				str := fmt.Sprintf("%s   %s", i.Name(), common.Gold(auc.Buyout))
 				r.Bargains = append(r.Bargains, str)
 			}
 
 			if appearanceSetBargain(i, auc, app) {
-				r.AppearanceBargains = append(r.AppearanceBargains, i.Name()+" ---")
+				r.AppearanceSetBargains = append(r.AppearanceSetBargains, appearanceSetLabel(i, app))
 			} else {
 				// The item is already a bargain, no need to check again
 				if appearanceBargain(i, auc, app) {
@@ -180,29 +289,51 @@ func (r *Recommendations) iterateAuctions(auctions map[int64][]auction.Auction,
 	}
 }
 
+// iterateBidOnly checks each auction that accepts bids, but not purchases, for recommendation
+func (r *Recommendations) iterateBidOnly(auctions map[int64][]auction.Auction, app *application.App) {
+	for itemID, itemAuctions := range auctions {
+		i, ok := r.getItem(itemID, app)
+		if !ok {
+			continue
+		}
+
+		for _, auc := range itemAuctions {
+			profit, ok := bidBargain(i, auc, app)
+			if ok {
+				str := fmt.Sprintf("%s   %s  (bid %s, %s)", i.Name(), common.Gold(profit), common.Gold(auc.Bid), auc.TimeLeft)
+				r.BidBargains = append(r.BidBargains, str)
+			}
+		}
+	}
+}
+
 // scanRealm retrieves auctions and prints suggestions for what to buy for a single realm
 func scanRealm(realm string, c chan<- Recommendations, app *application.App) {
 	r := Recommendations{
 		Realm: realm,
 	}
 
-	auctions, err := auction.Get(realm)
+	house, err := auction.Get(realm)
 	if err != nil {
 		r.Err = err
 		c <- r
 		return
 	}
 
-	r.NumUniqueItems = len(auctions)
-	r.iterateAuctions(auctions, realm == "Commodities", app)
+	r.Diagnostics = house.Diagnostics
+	r.NumUniqueItems = len(house.Auctions)
+	r.iterateAuctions(house.Auctions, realm == "Commodities", app)
+	r.iterateBidOnly(house.BidOnly, app)
 
 	c <- r
 }
 
-// scanRealms processes auctions on all realms in 'r'
-func scanRealms(r string, app *application.App) []Recommendations {
+// scanRealms processes auctions on all realms in 'r'. Realms that could not be scanned, and
+// auctions and items that were skipped, are returned as diagnostics.
+func scanRealms(r string, app *application.App) ([]Recommendations, []diagnostic.Diagnostic) {
 	realms := strings.Split(r, ",")
 	results := []Recommendations{}
+	diagnostics := []diagnostic.Diagnostic{}
 	c := make(chan Recommendations)
 
 	for _, realm := range realms {
@@ -213,13 +344,14 @@ func scanRealms(r string, app *application.App) []Recommendations {
 	for range len(realms) {
 		r := <-c
 		if r.Err != nil {
-			fmt.Fprintf(os.Stderr, "*** failed to scan realm %s: %s\n", r.Realm, r.Err)
+			diagnostics = append(diagnostics, diagnostic.Diagnostic{Realm: r.Realm, Err: fmt.Errorf("failed to scan realm: %w", r.Err)})
 			continue
 		}
+		diagnostics = append(diagnostics, r.Diagnostics...)
 		results = append(results, r)
 	}
 
-	return results
+	return results, diagnostics
 }
 
 // fmtShoppingList returns a formatted string of the given items or "" if none
@@ -245,6 +377,7 @@ func (r *Recommendations) format(app *application.App, summarize bool) string {
 	shoppingList += fmtShoppingList("Pets I Need", r.PetNeededBargains, output.FgMagenta, summarize)
 	shoppingList += fmtShoppingList("Pets to Resell", r.PetResellBargains, output.FgGreen, summarize)
 	shoppingList += fmtShoppingList("Useful Item Bargains", r.Bargains, output.FgRed, summarize)
+	shoppingList += fmtShoppingList("Appearance Set Bargains", r.AppearanceSetBargains, output.FgBlue, summarize)
 	shoppingList += fmtShoppingList("Appearance Bargains", r.AppearanceBargains, output.FgBlue, summarize)
 
 	if summarize {
@@ -253,6 +386,8 @@ func (r *Recommendations) format(app *application.App, summarize bool) string {
 		}
 	} else {
 		shoppingList += fmtShoppingList("Arbitrages", r.Arbitrages, output.FgWhite, summarize)
+		shoppingList += fmtShoppingList("Arbitrages at Estimated Prices", r.EstimatedArbitrages, output.FgWhite, summarize)
+		shoppingList += fmtShoppingList("Bids Below Vendor Price, Ending Soon", r.BidBargains, output.FgWhite, summarize)
 	}
 
 	if len(shoppingList) == 0 {
@@ -319,6 +454,18 @@ func generateOutput(app *application.App, recommendations []Recommendations) err
 		return err
 	}
 
+	// The prices file is current again; clear any mark left by wowctl refresh
+	err = os.Remove(app.Paths.PriceCacheStale)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Appearances needed, seen while scanning
+	err = os.WriteFile(app.Paths.AppearancesNeeded, []byte(app.Appearances.Report()), 0600)
+	if err != nil {
+		return err
+	}
+
 	// Recipes needed
 	err = os.WriteFile(app.Paths.RecipesNeeded, []byte(app.Cooking.Output()), 0600)
 	if err != nil {
@@ -338,13 +485,29 @@ func generateOutput(app *application.App, recommendations []Recommendations) err
 func Shop(realms string, app *application.App) error {
 	var err error
 
-	recommendations := scanRealms(realms, app)
+	recommendations, diagnostics := scanRealms(realms, app)
 
 	err = generateOutput(app, recommendations)
 	if err != nil {
 		return err
 	}
 
+	// The web API data we could not use; see 'Maintenance Items' in the README
+	report := diagnostic.Report(diagnostics)
+	if report != "" {
+		fmt.Fprintf(os.Stderr, "\n*** %s", report)
+	}
+	err = os.WriteFile(app.Paths.Maintenance, []byte(report), 0600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: failed to save maintenance report: %s\n", err)
+	}
+
+	// Keep the profession tool iLevels learned from this scan
+	err = app.ILevels.Save()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: failed to save profession tool iLevels: %s\n", err)
+	}
+
 	// Most runs do not change the persistence; be frugal about whether to save
 	if app.WowItem.Dirty() {
 		err = app.WowItem.Save()
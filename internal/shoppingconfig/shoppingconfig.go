@@ -1,6 +1,11 @@
 package shoppingconfig
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
 	"github.com/erikbryant/wow/internal/common"
 	"github.com/erikbryant/wow/internal/cooking"
 	"github.com/erikbryant/wow/internal/wowitem"
@@ -12,6 +17,9 @@ type UserConfig struct {
 	ArbitrageProfitMin       int64
 	BattlePetPriceResellMax  int64
 	BattlePetPriceUnownedMax int64
+	BidExpiryMax             time.Duration
+	HeirloomPriceMax         int64
+	MountPriceMax            int64
 	ProfitToDisplayMin       int64
 	RecipePriceMax           int64
 	ToyPriceMax              int64
@@ -20,50 +28,62 @@ type UserConfig struct {
 	SkipPets    map[int64]struct{}
 }
 
-func New(wi *wowitem.Persistence, cr *cooking.CookingRecipes) *UserConfig {
-	//bagPriceMax := common.Coppers(100, 0, 0)
-	//reagentBagPriceMax := common.Coppers(100, 0, 0)
+// wish is a useful item I want, looked up by name
+type wish struct {
+	name     string
+	priceMax int64
+	wowitem.Disambiguation
+}
+
+// wishlist holds useful items I want, if the price is right. Names must
+// match exactly one item; if several items share a name, narrow the match
+// with a class, ilevel or quality.
+var wishlist = []wish{
+	// Bags
+	//{name: "Weavercloth Bag", priceMax: common.Coppers(100, 0, 0)},              // 34 slot
+	//{name: "Azureweave Expedition Pack", priceMax: common.Coppers(100, 0, 0)},   // 34 slot
+	//{name: "Imbued Bright Linen Backpack", priceMax: common.Coppers(100, 0, 0)}, // 36 slot
+	//{name: "Duskweave Bag", priceMax: common.Coppers(100, 0, 0)},                // 36 slot
+	//{name: "Sunfire Silk Backpack", priceMax: common.Coppers(100, 0, 0)},        // 38 slot
 
+	// Reagent bags
+	//{name: "Chronocloth Reagent Bag", priceMax: common.Coppers(100, 0, 0)},      // 36 slot
+	//{name: "Weavercloth Reagent Bag", priceMax: common.Coppers(100, 0, 0)},      // 36 slot
+	//{name: "Dawnweave Reagent Bag", priceMax: common.Coppers(100, 0, 0)},        // 38 slot
+	//{name: "Bright Linen Reagent Satchel", priceMax: common.Coppers(100, 0, 0)}, // 38 slot
+	//{name: "Arcanoweave Reagent Rucksack", priceMax: common.Coppers(100, 0, 0)}, // 40 slot
+
+	// Fun weapon appearances
+	{name: "Blackfury", priceMax: common.Coppers(3000, 0, 0)},
+	{name: "Tyrhold Broadsword", priceMax: common.Coppers(3000, 0, 0)},
+	{name: "Ameelton's Shot-Thrower", priceMax: common.Coppers(3000, 0, 0)},
+	{name: "Kickback 5000", priceMax: common.Coppers(3000, 0, 0)},
+	{name: "Extreme-Impact Hole Puncher", priceMax: common.Coppers(3000, 0, 0)},
+
+	// Appearance set appearances
+	{name: "Tyrhold Visage", priceMax: common.Coppers(2000, 0, 0)},
+	{name: "Boots of the Black Flame", priceMax: common.Coppers(2000, 0, 0)},
+	{name: "Helm of the Tranquil Path", priceMax: common.Coppers(2000, 0, 0)},
+}
+
+// New returns the shopping configuration. It is an error if any wishlist
+// or needed recipe name is missing from the item persistence or ambiguous.
+func New(wi *wowitem.Persistence, cr *cooking.CookingRecipes) (*UserConfig, error) {
 	userConfig := UserConfig{
 		AppearancePriceMax:       common.Coppers(50, 0, 0),
 		AppearancePriceInSetMax:  common.Coppers(600, 0, 0),
 		ArbitrageProfitMin:       common.Coppers(0, 50, 0),
 		BattlePetPriceResellMax:  common.Coppers(180, 0, 0),
 		BattlePetPriceUnownedMax: common.Coppers(500, 0, 0),
+		BidExpiryMax:             2 * time.Hour, // 0 turns off bid bargains
+		HeirloomPriceMax:         common.Coppers(1000, 0, 0),
+		MountPriceMax:            common.Coppers(2000, 0, 0),
 		ProfitToDisplayMin:       common.Coppers(15, 0, 0),
 		RecipePriceMax:           common.Coppers(19, 0, 0),
 		ToyPriceMax:              common.Coppers(400, 0, 0),
 
-		// UsefulGoods are useful items I want, if the price is right
-		// If the item name fails to match wi.Search will print a warning
-		// the user can then come in here and fix the name.
-		UsefulGoods: map[int64]int64{
-			// Bags
-			//wi.Search("Weavercloth Bag").ID():              bagPriceMax, // 34 slot
-			//wi.Search("Azureweave Expedition Pack").ID():   bagPriceMax, // 34 slot
-			//wi.Search("Imbued Bright Linen Backpack").ID(): bagPriceMax, // 36 slot
-			//wi.Search("Duskweave Bag").ID():                bagPriceMax, // 36 slot
-			//wi.Search("Sunfire Silk Backpack").ID():        bagPriceMax, // 38 slot
-
-			// Reagent bags
-			//wi.Search("Chronocloth Reagent Bag").ID():      reagentBagPriceMax, // 36 slot
-			//wi.Search("Weavercloth Reagent Bag").ID():      reagentBagPriceMax, // 36 slot
-			//wi.Search("Dawnweave Reagent Bag").ID():        reagentBagPriceMax, // 38 slot
-			//wi.Search("Bright Linen Reagent Satchel").ID(): reagentBagPriceMax, // 38 slot
-			//wi.Search("Arcanoweave Reagent Rucksack").ID(): reagentBagPriceMax, // 40 slot
-
-			// Fun weapon appearances
-			wi.Search("Blackfury").ID():                   common.Coppers(3000, 0, 0),
-			wi.Search("Tyrhold Broadsword").ID():          common.Coppers(3000, 0, 0),
-			wi.Search("Ameelton's Shot-Thrower").ID():     common.Coppers(3000, 0, 0),
-			wi.Search("Kickback 5000").ID():               common.Coppers(3000, 0, 0),
-			wi.Search("Extreme-Impact Hole Puncher").ID(): common.Coppers(3000, 0, 0),
-
-			// Appearance set appearances
-			wi.Search("Tyrhold Visage").ID():            common.Coppers(2000, 0, 0),
-			wi.Search("Boots of the Black Flame").ID():  common.Coppers(2000, 0, 0),
-			wi.Search("Helm of the Tranquil Path").ID(): common.Coppers(2000, 0, 0),
-		},
+		// UsefulGoods are useful items I want, if the price is right; see wishlist
+		UsefulGoods: map[int64]int64{},
 
 		// SkipPets holds SpeciesID of pets that do not resell well
 		SkipPets: map[int64]struct{}{
@@ -120,9 +140,28 @@ func New(wi *wowitem.Persistence, cr *cooking.CookingRecipes) *UserConfig {
 	}
 
 	// Add any recipes that the user needs
+	wishes := slices.Clone(wishlist)
 	for _, recipeName := range cr.RecipesNeeded() {
-		userConfig.UsefulGoods[wi.Search(recipeName).ID()] = userConfig.RecipePriceMax
+		wishes = append(wishes, wish{
+			name:           recipeName,
+			priceMax:       userConfig.RecipePriceMax,
+			Disambiguation: wowitem.Disambiguation{Class: "Recipe"},
+		})
+	}
+
+	// Report every bad name at once so they can all be fixed together
+	errs := []error{}
+	for _, w := range wishes {
+		item, err := wi.LookupOne(w.name, w.Disambiguation)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		userConfig.UsefulGoods[item.ID()] = w.priceMax
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("shopping list names must match exactly one item:\n%w", err)
 	}
 
-	return &userConfig
+	return &userConfig, nil
 }
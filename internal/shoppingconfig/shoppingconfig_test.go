@@ -1,16 +1,44 @@
 package shoppingconfig
 
 import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/erikbryant/wow/internal/cooking"
 	"github.com/erikbryant/wow/internal/wowitem"
 )
 
-func TestNewDefaults(t *testing.T) {
+func configItem(id int64, name, class string) wowitem.Item {
+	return wowitem.Item{XID: id, XItem: map[string]any{
+		"id":         json.Number(strconv.FormatInt(id, 10)),
+		"name":       name,
+		"level":      json.Number("1"),
+		"item_class": map[string]any{"name": class},
+	}}
+}
+
+// wishlistItems returns a persistence holding one item for every wishlist entry
+func wishlistItems(t *testing.T) *wowitem.Persistence {
+	t.Helper()
+
 	wi := wowitem.NewEmpty(t.TempDir() + "/items")
+	for i, w := range wishlist {
+		id := int64(1000 + i)
+		wi.Set(id, configItem(id, w.name, "Weapon"))
+	}
+	return wi
+}
+
+func TestNewDefaults(t *testing.T) {
+	wi := wishlistItems(t)
 	cr := &cooking.CookingRecipes{}
-	c := New(wi, cr)
+	c, err := New(wi, cr)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
 	if c.AppearancePriceMax <= 0 || c.AppearancePriceInSetMax <= c.AppearancePriceMax || c.ProfitToDisplayMin <= 0 {
 		t.Fatal("unexpected defaults")
 	}
@@ -19,7 +47,33 @@ func TestNewDefaults(t *testing.T) {
 			t.Errorf("missing skip pet %d", id)
 		}
 	}
-	if len(c.UsefulGoods) == 0 {
-		t.Error("expected useful goods")
+	if len(c.UsefulGoods) != len(wishlist) {
+		t.Errorf("UsefulGoods has %d entries, want %d", len(c.UsefulGoods), len(wishlist))
+	}
+	if _, ok := c.UsefulGoods[0]; ok {
+		t.Error("UsefulGoods has item ID 0")
+	}
+}
+
+func TestNewMissingName(t *testing.T) {
+	wi := wowitem.NewEmpty(t.TempDir() + "/items")
+	_, err := New(wi, &cooking.CookingRecipes{})
+	if !errors.Is(err, wowitem.ErrNotFound) {
+		t.Fatalf("New() error = %v, want ErrNotFound", err)
+	}
+	// Every missing name is reported, not just the first
+	for _, w := range wishlist {
+		if !strings.Contains(err.Error(), w.name) {
+			t.Errorf("error does not mention %q", w.name)
+		}
+	}
+}
+
+func TestNewAmbiguousName(t *testing.T) {
+	wi := wishlistItems(t)
+	wi.Set(1, configItem(1, wishlist[0].name, "Weapon"))
+	_, err := New(wi, &cooking.CookingRecipes{})
+	if !errors.Is(err, wowitem.ErrAmbiguous) {
+		t.Fatalf("New() error = %v, want ErrAmbiguous", err)
 	}
 }
@@ -0,0 +1,140 @@
+package syntheticitem
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Spec is a synthetic item as kept in the synthetic items data file
+type Spec struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Level     int64  `json:"level"`
+	Price     int64  `json:"price,omitempty"` // vendor sell price, in coppers
+	Commodity bool   `json:"commodity,omitempty"`
+	Class     string `json:"class,omitempty"`
+}
+
+// Item returns the synthetic item described by s
+func (s Spec) Item() *Item {
+	item := New(s.ID, s.Name)
+
+	if s.Level != 0 {
+		item.SetItemLevel(s.Level)
+	}
+	if s.Price != 0 {
+		item.SetPreviewPrice(s.Price)
+	}
+	if s.Class != "" {
+		item.SetItemClassName(s.Class)
+	}
+	item.SetStackable(s.Commodity)
+
+	return item
+}
+
+// validate returns an error if the spec could not describe a real item
+func (s Spec) validate() error {
+	if s.ID <= 0 {
+		return fmt.Errorf("synthetic item id must be positive got %d", s.ID)
+	}
+	if s.Name == "" {
+		return fmt.Errorf("synthetic item %d has no name", s.ID)
+	}
+	if s.Level < 0 {
+		return fmt.Errorf("synthetic item %d has negative level %d", s.ID, s.Level)
+	}
+	if s.Price < 0 {
+		return fmt.Errorf("synthetic item %d has negative price %d", s.ID, s.Price)
+	}
+	return nil
+}
+
+// Load reads the synthetic items data file
+func Load(filename string) ([]Spec, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read synthetic items: %w", err)
+	}
+
+	specs := []Spec{}
+	err = json.Unmarshal(data, &specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	seen := map[int64]bool{}
+	for _, s := range specs {
+		err = s.validate()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		if seen[s.ID] {
+			return nil, fmt.Errorf("%s: synthetic item %d is listed more than once", filename, s.ID)
+		}
+		seen[s.ID] = true
+	}
+
+	return specs, nil
+}
+
+// Save writes the synthetic items data file, sorted by ID
+func Save(filename string, specs []Spec) error {
+	specs = slices.Clone(specs)
+	slices.SortFunc(specs, func(a, b Spec) int { return cmp.Compare(a.ID, b.ID) })
+
+	data, err := json.MarshalIndent(specs, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	// Write to a temp file and rename so a failed write cannot truncate the data file
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to save synthetic items: %w", err)
+	}
+
+	_, err = f.Write(data)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to save synthetic items: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to save synthetic items: %w", err)
+	}
+
+	return os.Rename(f.Name(), filename)
+}
+
+// Add returns specs with s added. It is an error if s is invalid or its ID is already listed.
+func Add(specs []Spec, s Spec) ([]Spec, error) {
+	err := s.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.ContainsFunc(specs, func(x Spec) bool { return x.ID == s.ID }) {
+		return nil, fmt.Errorf("synthetic item %d already exists", s.ID)
+	}
+
+	return append(slices.Clone(specs), s), nil
+}
+
+// Remove returns specs without the item with this ID. It is an error if it is not listed.
+func Remove(specs []Spec, id int64) ([]Spec, error) {
+	i := slices.IndexFunc(specs, func(x Spec) bool { return x.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("synthetic item %d does not exist", id)
+	}
+
+	return slices.Delete(slices.Clone(specs), i, i+1), nil
+}
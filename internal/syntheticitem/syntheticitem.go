@@ -19,6 +19,7 @@ func New(id int64, name string) *Item {
 	i.SetItemLevel(1)
 	i.SetStackable(false)
 	i.SetItemClassName("Miscellaneous")
+	i.set([]string{"provenance", "type"}, "synthetic")
 
 	return &i
 }
@@ -52,6 +53,12 @@ func (i *Item) SetPreviewPrice(value int64) *Item {
 	return i
 }
 
+// SetSource records what supplied the item data
+func (i *Item) SetSource(source string) *Item {
+	i.set([]string{"provenance", "source"}, source)
+	return i
+}
+
 func (i *Item) SetName(name string) *Item {
 	i.set([]string{"name"}, name)
 	return i
@@ -1,7 +1,10 @@
 package syntheticitem
 
 import (
+	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"testing"
@@ -9,6 +12,16 @@ import (
 	"github.com/erikbryant/wow/internal/wowitem"
 )
 
+func wowItem(t *testing.T, data map[string]any) *wowitem.Item {
+	t.Helper()
+
+	wi, err := wowitem.NewItem(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wi
+}
+
 func TestNew(t *testing.T) {
 	item := New(123456, "test name")
 
@@ -27,6 +40,10 @@ func TestNew(t *testing.T) {
 	if got := item.Map()["is_stackable"]; got != false {
 		t.Errorf("is_stackable = %#v, want false", got)
 	}
+
+	if wi := wowItem(t, item.SetSource("synthetics.json").Map()); !wi.Synthetic() || wi.Source() != "synthetics.json" {
+		t.Errorf("provenance = %q source %q, want synthetic from synthetics.json", wi.Provenance(), wi.Source())
+	}
 }
 
 func TestNewIDBoundaries(t *testing.T) {
@@ -224,7 +241,7 @@ func TestSyntheticIsItem(t *testing.T) {
 		SetPreviewPrice(123456).
 		SetName("test")
 
-	wi := wowitem.NewItem(item.Map())
+	wi := wowItem(t, item.Map())
 
 	if got := wi.ID(); got != 16 {
 		t.Errorf("level = %#v, want %d", 16, got)
@@ -252,6 +269,126 @@ func TestSyntheticIsItem(t *testing.T) {
 
 }
 
+func TestSpecItem(t *testing.T) {
+	spec := Spec{ID: 226001, Name: "Pure Gold Stein", Level: 23, Price: 2000000, Commodity: true, Class: "Consumable"}
+	wi := wowItem(t, spec.Item().Map())
+
+	if wi.ID() != 226001 || wi.Name() != "Pure Gold Stein" || wi.ItemLevel() != 23 {
+		t.Errorf("got %d %q level %d", wi.ID(), wi.Name(), wi.ItemLevel())
+	}
+	if wi.SellPriceAdvertised() != 2000000 || !wi.Stackable() || wi.ItemClassName() != "Consumable" {
+		t.Errorf("got price %d stackable %t class %q", wi.SellPriceAdvertised(), wi.Stackable(), wi.ItemClassName())
+	}
+
+	// Unset fields take the New defaults
+	wi = wowItem(t, Spec{ID: 1, Name: "Water Stone"}.Item().Map())
+	if wi.ItemLevel() != 1 || wi.SellPriceAdvertised() != 0 || wi.Stackable() || wi.ItemClassName() != "Miscellaneous" {
+		t.Errorf("got level %d price %d stackable %t class %q", wi.ItemLevel(), wi.SellPriceAdvertised(), wi.Stackable(), wi.ItemClassName())
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "synthetics.json")
+	specs := []Spec{
+		{ID: 3, Name: "Three", Level: 1},
+		{ID: 1, Name: "One", Level: 5, Price: 75, Commodity: true, Class: "Consumable"},
+	}
+
+	err := Save(filename, specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Spec{specs[1], specs[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"syntax":    `[{"id": 1,`,
+		"duplicate": `[{"id": 1, "name": "a", "level": 1}, {"id": 1, "name": "b", "level": 1}]`,
+		"no name":   `[{"id": 1, "level": 1}]`,
+		"bad id":    `[{"id": 0, "name": "a", "level": 1}]`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "synthetics.json")
+			if err := os.WriteFile(filename, []byte(data), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(filename); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	specs := []Spec{{ID: 1, Name: "One", Level: 1}}
+
+	specs, err := Add(specs, Spec{ID: 2, Name: "Two", Level: 1})
+	if err != nil || len(specs) != 2 {
+		t.Fatalf("Add = %+v, %v", specs, err)
+	}
+
+	if _, err = Add(specs, Spec{ID: 2, Name: "Again", Level: 1}); err == nil {
+		t.Error("expected error adding duplicate id")
+	}
+	if _, err = Add(specs, Spec{ID: 3, Level: 1}); err == nil {
+		t.Error("expected error adding item without name")
+	}
+
+	specs, err = Remove(specs, 1)
+	if err != nil || len(specs) != 1 || specs[0].ID != 2 {
+		t.Fatalf("Remove = %+v, %v", specs, err)
+	}
+
+	if _, err = Remove(specs, 1); err == nil {
+		t.Error("expected error removing missing id")
+	}
+}
+
+// The checked-in data file must load and already be in the form Save writes
+func TestCheckedInSynthetics(t *testing.T) {
+	filename := filepath.Join("..", "..", "data", "synthetics.json")
+
+	specs, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := filepath.Join(t.TempDir(), "synthetics.json")
+	if err := Save(saved, specs); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Error("data/synthetics.json is not formatted as Save writes it")
+	}
+}
+
 func assertNestedValue(t *testing.T, data map[string]any, keys []string, want any) {
 	t.Helper()
 
@@ -3,18 +3,43 @@ package toy
 import (
 	"testing"
 
+	"github.com/erikbryant/wow/internal/collectible"
 	"github.com/erikbryant/wow/internal/wowitem"
 )
 
-func toyItem(name string) wowitem.Item {
-	return wowitem.Item{XID: 1, XItem: map[string]any{"name": name}}
+func toyItem(id int64, name, toy string) wowitem.Item {
+	return wowitem.Item{XID: id, XItem: map[string]any{"name": name, "preview_item": map[string]any{"toy": toy}}}
 }
+
+// testSource is source with the teaching item of toy N being item N/100
+func testSource() collectible.Source {
+	s := source
+	s.ItemOf = func(id int64) (int64, error) { return id / 100, nil }
+	return s
+}
+
 func TestOwned(test *testing.T) {
-	t := &Toy{names: map[string]int64{"Toy A": 100, "Toy B": 200}, owned: map[int64]bool{100: true}}
-	if !t.Owned(toyItem("Toy A")) {
+	t := &Toy{Collection: collectible.NewWithData(testSource(), map[string][]int64{"Toy A": {100}, "Toy B": {200}}, map[int64]bool{100: true})}
+	if !t.Owned(toyItem(1, "Toy A", "Toy")) {
 		test.Error("owned toy reported false")
 	}
-	if t.Owned(toyItem("Toy B")) || t.Owned(toyItem("Not a toy")) {
+	if t.Owned(toyItem(2, "Toy B", "Toy")) || t.Owned(toyItem(3, "Not a toy", "Toy")) {
 		test.Error("unowned/non-toy reported true")
 	}
+	if !t.Need(toyItem(2, "Toy B", "Toy")) {
+		test.Error("unowned toy not needed")
+	}
+	if t.Need(toyItem(4, "Toy B", "")) {
+		test.Error("item that is not a toy should not be needed")
+	}
+}
+
+func TestOwnedByItem(test *testing.T) {
+	t := &Toy{Collection: collectible.NewWithData(testSource(), map[string][]int64{"Twin": {100, 200}}, map[int64]bool{100: true})}
+	if !t.Owned(toyItem(1, "Twin", "Toy")) || t.Need(toyItem(1, "Twin", "Toy")) {
+		test.Error("item 1 teaches the owned twin")
+	}
+	if t.Owned(toyItem(2, "Twin", "Toy")) || !t.Need(toyItem(2, "Twin", "Toy")) {
+		test.Error("item 2 teaches the unowned twin")
+	}
 }
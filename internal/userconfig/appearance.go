@@ -2,17 +2,45 @@ package userconfig
 
 import (
 	"fmt"
+	"maps"
 	"slices"
+	"strings"
+	"sync"
 
 	"github.com/erikbryant/wow/internal/common"
 	"github.com/erikbryant/wow/internal/wowapi"
+	"github.com/erikbryant/wow/internal/wowitem"
 )
 
+// neededAppearance records an item that would provide an appearance I do not own
+type neededAppearance struct {
+	itemID   int64
+	itemName string
+	slot     string
+}
+
 type Appearances struct {
-	owned map[int64]bool
+	// owned maps appearance ID to the slot type it was collected in
+	owned map[int64]string
+
+	// needed collects diagnostics while scanning; several realms scan at once
+	mu     sync.Mutex
+	needed map[int64]neededAppearance
 }
 
 var (
+	// excludedSlots are slot types whose appearances are not real transmogs;
+	// they generate false positives
+	excludedSlots = map[string]struct{}{
+		"PROFESSION_TOOL": {},
+		"PROFESSION_GEAR": {},
+	}
+
+	// excludedSubclasses are item subclasses whose appearances are not worth collecting
+	excludedSubclasses = map[string]struct{}{
+		"Fishing Poles": {},
+	}
+
 	// excludedIDs are appearance IDs that are problematic in one way or another
 	excludedIDs = map[int64]struct{}{
 		// These are not real appearances; they generate false positives
@@ -122,23 +150,54 @@ var (
 	}
 )
 
-// getOwned returns the appearance IDs I own
-func getOwned() (map[int64]bool, error) {
+// getOwned returns the appearance IDs I own and the slot each was collected in
+func getOwned() (map[int64]string, error) {
 	t, err := wowapi.CollectionsTransmogs()
 	if err != nil {
 		return nil, fmt.Errorf("unable to obtain transmogs owned: %w", err)
 	}
 
-	ao := map[int64]bool{}
+	return parseOwned(t)
+}
+
+// parseOwned reads the "slots" of a /collections/transmogs response (see wowapi/README.md)
+func parseOwned(t any) (map[int64]string, error) {
+	ao := map[int64]string{}
+
+	transmogs, ok := t.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("transmogs: expected object response, got %T", t)
+	}
 
-	transmogs := t.(map[string]any)
+	slots, ok := transmogs["slots"].([]any)
+	if !ok {
+		return nil, fmt.Errorf("transmogs: slots has type %T, want []any", transmogs["slots"])
+	}
 
-	for _, slot := range transmogs["slots"].([]any) {
-		slot := slot.(map[string]any)
-		for _, appearance := range slot["appearances"].([]any) {
-			appearance := appearance.(map[string]any)
-			id := common.JSONInt64Panic(appearance["id"])
-			ao[id] = true
+	for _, rawSlot := range slots {
+		slot, ok := rawSlot.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("transmogs: slot has type %T, want map[string]any", rawSlot)
+		}
+		s, ok := slot["slot"].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("transmogs: slot.slot has type %T, want map[string]any", slot["slot"])
+		}
+		slotType := common.JSONString(s["type"])
+		appearances, ok := slot["appearances"].([]any)
+		if !ok {
+			return nil, fmt.Errorf("transmogs: %s appearances has type %T, want []any", slotType, slot["appearances"])
+		}
+		for _, rawAppearance := range appearances {
+			appearance, ok := rawAppearance.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("transmogs: %s appearance has type %T, want map[string]any", slotType, rawAppearance)
+			}
+			id, err := common.JSONInt64(appearance["id"])
+			if err != nil {
+				return nil, fmt.Errorf("transmogs: %s appearance id: %w", slotType, err)
+			}
+			ao[id] = slotType
 		}
 	}
 
@@ -146,7 +205,9 @@ func getOwned() (map[int64]bool, error) {
 }
 
 func NewAppearances() (*Appearances, error) {
-	ao := Appearances{}
+	ao := Appearances{
+		needed: map[int64]neededAppearance{},
+	}
 	var err error
 
 	ao.owned, err = getOwned()
@@ -155,8 +216,8 @@ func NewAppearances() (*Appearances, error) {
 	}
 
 	// Make sure I don't already own any of the items I am filtering.
-	for id, _ := range excludedIDs {
-		if ao.owned[id] {
+	for id := range excludedIDs {
+		if _, ok := ao.owned[id]; ok {
 			fmt.Printf("You already own this, remove it from excludedIDs: %d\n", id)
 		}
 	}
@@ -164,6 +225,16 @@ func NewAppearances() (*Appearances, error) {
 	return &ao, nil
 }
 
+// excludedItem returns true if appearances from this item are never worth collecting
+func excludedItem(i wowitem.Item) bool {
+	// Item inventory types and transmog slot types share names for the slots we exclude
+	if _, ok := excludedSlots[i.InventoryType()]; ok {
+		return true
+	}
+	_, ok := excludedSubclasses[i.ItemSubclassName()]
+	return ok
+}
+
 // needID returns true if I need this appearance ID
 func (ao *Appearances) needID(id int64) bool {
 	_, ok := excludedIDs[id]
@@ -171,19 +242,76 @@ func (ao *Appearances) needID(id int64) bool {
 		return false
 	}
 
-	if !ao.owned[id] {
-		fmt.Println("NEED APPEARANCE ID: ", id)
+	_, owned := ao.owned[id]
+	return !owned
+}
+
+// Need returns true if I need any of the appearances this item provides
+func (ao *Appearances) Need(i wowitem.Item) bool {
+	if excludedItem(i) {
+		return false
+	}
+
+	need := false
+
+	for _, id := range i.Appearances() {
+		if ao.needID(id) {
+			need = true
+			ao.record(id, i)
+		}
 	}
 
-	return !ao.owned[id]
+	return need
+}
+
+// record notes that this item provides an appearance I need, for the report. When
+// several items provide it, the lowest item ID is kept so the report does not depend
+// on the order the realms were scanned in.
+func (ao *Appearances) record(id int64, i wowitem.Item) {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+
+	if ao.needed == nil {
+		ao.needed = map[int64]neededAppearance{}
+	}
+	if n, ok := ao.needed[id]; ok && n.itemID <= i.ID() {
+		return
+	}
+	ao.needed[id] = neededAppearance{itemID: i.ID(), itemName: i.Name(), slot: i.InventoryType()}
 }
 
-// Need returns true if I need any of these appearance ids
-func (ao *Appearances) Need(appearanceIDs []int64) bool {
-	return slices.ContainsFunc(appearanceIDs, ao.needID)
+// Report returns the appearances owned per slot and the appearance IDs seen while scanning that I need
+func (ao *Appearances) Report() string {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+
+	var report strings.Builder
+
+	report.WriteString("Appearances owned by slot:\n")
+	bySlot := map[string]int{}
+	for _, slot := range ao.owned {
+		bySlot[slot]++
+	}
+	for _, slot := range slices.Sorted(maps.Keys(bySlot)) {
+		report.WriteString(fmt.Sprintf("%-20s %6d\n", slot, bySlot[slot]))
+	}
+
+	report.WriteString("\nAppearances needed:\n")
+	for _, id := range slices.Sorted(maps.Keys(ao.needed)) {
+		n := ao.needed[id]
+		report.WriteString(fmt.Sprintf("%6d  %-16s %7d  %s\n", id, n.slot, n.itemID, n.itemName))
+	}
+
+	return report.String()
 }
 
 // Len returns the number of entries.
 func (ao *Appearances) Len() int {
 	return len(ao.owned)
 }
+
+// Owned returns true if I own this appearance ID
+func (ao *Appearances) Owned(id int64) bool {
+	_, ok := ao.owned[id]
+	return ok
+}
@@ -1,22 +1,117 @@
 package userconfig
 
-import "testing"
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/erikbryant/wow/internal/wowitem"
+)
+
+func appearanceItem(id int64, slot, subclass string, appearanceIDs ...int64) wowitem.Item {
+	appearances := []any{}
+	for _, a := range appearanceIDs {
+		appearances = append(appearances, map[string]any{"id": json.Number(strconv.FormatInt(a, 10))})
+	}
+	return wowitem.Item{XID: id, XItem: map[string]any{
+		"name":           "Item " + strconv.FormatInt(id, 10),
+		"inventory_type": map[string]any{"type": slot},
+		"item_subclass":  map[string]any{"name": subclass},
+		"appearances":    appearances,
+	}}
+}
 
 func TestAppearancesNeed(t *testing.T) {
-	a := &Appearances{owned: map[int64]bool{10: true, 20: false}}
-	if a.Need([]int64{10}) {
+	a := &Appearances{owned: map[int64]string{10: "HEAD", 11: "HEAD"}}
+	if a.Need(appearanceItem(1, "HEAD", "Plate", 10)) {
 		t.Error("owned appearance should not be needed")
 	}
-	if !a.Need([]int64{10, 20}) {
+	if !a.Need(appearanceItem(2, "HEAD", "Plate", 10, 20)) {
 		t.Error("unowned appearance should be needed")
 	}
-	if a.Need([]int64{573}) {
+	if a.Need(appearanceItem(3, "HOLDABLE", "Miscellaneous", 1884)) {
 		t.Error("excluded appearance should not be needed")
 	}
-	if a.Need(nil) {
-		t.Error("empty input should not be needed")
+	if a.Need(appearanceItem(4, "PROFESSION_TOOL", "Mining", 30)) {
+		t.Error("excluded slot should not be needed")
+	}
+	if a.Need(appearanceItem(5, "TWOHWEAPON", "Fishing Poles", 40)) {
+		t.Error("excluded subclass should not be needed")
+	}
+	if a.Need(appearanceItem(6, "HEAD", "Plate")) {
+		t.Error("item without appearances should not be needed")
+	}
+	for _, id := range []int64{573, 577, 78217} {
+		if a.Need(appearanceItem(7, "HEAD", "Miscellaneous", id)) {
+			t.Errorf("excluded appearance %d should not be needed", id)
+		}
 	}
 	if a.Len() != 2 {
 		t.Fail()
 	}
 }
+
+func TestAppearancesReportLowestItem(t *testing.T) {
+	a := &Appearances{owned: map[int64]string{}}
+	a.Need(appearanceItem(9, "HEAD", "Plate", 20))
+	a.Need(appearanceItem(2, "HEAD", "Plate", 20))
+	a.Need(appearanceItem(5, "HEAD", "Plate", 20))
+
+	if n := a.needed[20]; n.itemID != 2 {
+		t.Errorf("appearance 20 reported for item %d, want the lowest item ID 2", n.itemID)
+	}
+}
+
+func TestAppearancesReport(t *testing.T) {
+	a := &Appearances{owned: map[int64]string{10: "HEAD", 11: "HEAD", 12: "FEET"}}
+	a.Need(appearanceItem(2, "HEAD", "Plate", 10, 20))
+	a.Need(appearanceItem(4, "PROFESSION_TOOL", "Mining", 30))
+
+	report := a.Report()
+	if !strings.Contains(report, "HEAD") || !strings.Contains(report, "FEET") {
+		t.Errorf("missing slot counts: %s", report)
+	}
+	if !strings.Contains(report, "20  HEAD") || !strings.Contains(report, "Item 2") {
+		t.Errorf("missing needed appearance: %s", report)
+	}
+	if strings.Contains(report, "Item 4") {
+		t.Errorf("excluded item reported: %s", report)
+	}
+}
+
+func TestParseOwned(t *testing.T) {
+	transmogs := map[string]any{
+		"slots": []any{
+			map[string]any{
+				"slot":        map[string]any{"type": "HEAD", "name": "Head"},
+				"appearances": []any{map[string]any{"id": json.Number("358")}},
+			},
+			map[string]any{
+				"slot":        map[string]any{"type": "PROFESSION_TOOL", "name": "Profession Tool"},
+				"appearances": []any{},
+			},
+		},
+	}
+	owned, err := parseOwned(transmogs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owned) != 1 || owned[358] != "HEAD" {
+		t.Fatalf("owned=%v", owned)
+	}
+	if _, err := parseOwned(map[string]any{}); err == nil {
+		t.Error("missing slots should be an error")
+	}
+
+	for name, slot := range map[string]any{
+		"slot not an object":       "HEAD",
+		"missing slot type":        map[string]any{"appearances": []any{}},
+		"missing appearances":      map[string]any{"slot": map[string]any{"type": "HEAD"}},
+		"appearance not an object": map[string]any{"slot": map[string]any{"type": "HEAD"}, "appearances": []any{json.Number("358")}},
+	} {
+		if _, err := parseOwned(map[string]any{"slots": []any{slot}}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
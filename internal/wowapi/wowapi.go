@@ -2,6 +2,7 @@ package wowapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -27,6 +28,9 @@ var (
 	defaultClient   *Client
 )
 
+// ErrNotFound means the web API has no such resource (HTTP 404)
+var ErrNotFound = errors.New("not found")
+
 // NewClient returns a WoW API client. Since there is only one WoW web API,
 // we always return the default client.
 func NewClient() (*Client, error) {
@@ -76,6 +80,10 @@ func (c *Client) request(rawURL, token, caller string) (any, error) {
 	}
 	defer response.Body.Close()
 
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: HTTP status %d: %w", caller, response.StatusCode, ErrNotFound)
+	}
+
 	if response.StatusCode < http.StatusOK ||
 		response.StatusCode >= http.StatusMultipleChoices {
 		return nil, fmt.Errorf(
@@ -485,6 +493,30 @@ func (c *Client) Pets() ([]any, error) {
 	)
 }
 
+// Pet returns the details of a single battle pet species.
+func (c *Client) Pet(speciesID int64) (map[string]any, error) {
+	rawURL := fmt.Sprintf(
+		"%s/data/wow/pet/%d?namespace=static-us&locale=en_US",
+		c.apiBase,
+		speciesID,
+	)
+
+	r, err := c.request(rawURL, c.profileAccessToken, "Pet")
+	if err != nil {
+		return nil, err
+	}
+
+	response, ok := r.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(
+			"pet: expected object response, got %T",
+			r,
+		)
+	}
+
+	return response, nil
+}
+
 // CollectionsPets returns the battle pets the user owns.
 func (c *Client) CollectionsPets() ([]any, error) {
 	rawURL := c.apiBase +
@@ -511,6 +543,30 @@ func (c *Client) Toys() ([]any, error) {
 	)
 }
 
+// Toy returns the details of a single toy, including the item that teaches it.
+func (c *Client) Toy(toyID int64) (map[string]any, error) {
+	rawURL := fmt.Sprintf(
+		"%s/data/wow/toy/%d?namespace=static-us&locale=en_US",
+		c.apiBase,
+		toyID,
+	)
+
+	r, err := c.request(rawURL, c.profileAccessToken, "Toy")
+	if err != nil {
+		return nil, err
+	}
+
+	response, ok := r.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(
+			"toy: expected object response, got %T",
+			r,
+		)
+	}
+
+	return response, nil
+}
+
 // CollectionsToys returns the toys the user owns.
 func (c *Client) CollectionsToys() ([]any, error) {
 	rawURL := c.apiBase +
@@ -524,6 +580,82 @@ func (c *Client) CollectionsToys() ([]any, error) {
 	)
 }
 
+// Mounts returns a list of all mounts in the game.
+func (c *Client) Mounts() ([]any, error) {
+	rawURL := c.apiBase +
+		"/data/wow/mount/index?namespace=static-us&locale=en_US"
+
+	return c.requestKey(
+		rawURL,
+		c.profileAccessToken,
+		"mounts",
+		"Mounts",
+	)
+}
+
+// CollectionsMounts returns the mounts the user owns.
+func (c *Client) CollectionsMounts() ([]any, error) {
+	rawURL := c.apiBase +
+		"/profile/user/wow/collections/mounts?namespace=profile-us&locale=en_US"
+
+	return c.requestKey(
+		rawURL,
+		c.profileAccessToken,
+		"mounts",
+		"CollectionsMounts",
+	)
+}
+
+// Heirlooms returns a list of all heirlooms in the game.
+func (c *Client) Heirlooms() ([]any, error) {
+	rawURL := c.apiBase +
+		"/data/wow/heirloom/index?namespace=static-us&locale=en_US"
+
+	return c.requestKey(
+		rawURL,
+		c.profileAccessToken,
+		"heirlooms",
+		"Heirlooms",
+	)
+}
+
+// Heirloom returns the details of a single heirloom, including its item.
+func (c *Client) Heirloom(heirloomID int64) (map[string]any, error) {
+	rawURL := fmt.Sprintf(
+		"%s/data/wow/heirloom/%d?namespace=static-us&locale=en_US",
+		c.apiBase,
+		heirloomID,
+	)
+
+	r, err := c.request(rawURL, c.profileAccessToken, "Heirloom")
+	if err != nil {
+		return nil, err
+	}
+
+	response, ok := r.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(
+			"heirloom: expected object response, got %T",
+			r,
+		)
+	}
+
+	return response, nil
+}
+
+// CollectionsHeirlooms returns the heirlooms the user owns.
+func (c *Client) CollectionsHeirlooms() ([]any, error) {
+	rawURL := c.apiBase +
+		"/profile/user/wow/collections/heirlooms?namespace=profile-us&locale=en_US"
+
+	return c.requestKey(
+		rawURL,
+		c.profileAccessToken,
+		"heirlooms",
+		"CollectionsHeirlooms",
+	)
+}
+
 // ItemAppearanceSetsIndex returns IDs of each appearance set.
 func (c *Client) ItemAppearanceSetsIndex() ([]any, error) {
 	rawURL := c.apiBase +
@@ -698,6 +830,16 @@ func Pets() ([]any, error) {
 	return client.Pets()
 }
 
+// Pet returns the details of a single battle pet species.
+func Pet(speciesID int64) (map[string]any, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Pet(speciesID)
+}
+
 // CollectionsPets returns the battle pets the user owns.
 func CollectionsPets() ([]any, error) {
 	client, err := NewClient()
@@ -718,6 +860,16 @@ func Toys() ([]any, error) {
 	return client.Toys()
 }
 
+// Toy returns the details of a single toy, including the item that teaches it.
+func Toy(toyID int64) (map[string]any, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Toy(toyID)
+}
+
 // CollectionsToys returns the toys the user owns.
 func CollectionsToys() ([]any, error) {
 	client, err := NewClient()
@@ -728,6 +880,56 @@ func CollectionsToys() ([]any, error) {
 	return client.CollectionsToys()
 }
 
+// Mounts returns a list of all mounts in the game.
+func Mounts() ([]any, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Mounts()
+}
+
+// CollectionsMounts returns the mounts the user owns.
+func CollectionsMounts() ([]any, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.CollectionsMounts()
+}
+
+// Heirlooms returns a list of all heirlooms in the game.
+func Heirlooms() ([]any, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Heirlooms()
+}
+
+// Heirloom returns the details of a single heirloom, including its item.
+func Heirloom(heirloomID int64) (map[string]any, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Heirloom(heirloomID)
+}
+
+// CollectionsHeirlooms returns the heirlooms the user owns.
+func CollectionsHeirlooms() ([]any, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.CollectionsHeirlooms()
+}
+
 // ItemAppearanceSetsIndexIDs returns the ID and name of each appearance set.
 func ItemAppearanceSetsIndexIDs() (map[int64]string, error) {
 	client, err := NewClient()
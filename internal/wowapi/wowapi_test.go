@@ -2,6 +2,7 @@ package wowapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -406,6 +407,17 @@ func TestItem(t *testing.T) {
 	}
 }
 
+func TestItemNotFound(t *testing.T) {
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+
+	_, err := client.Item("12345")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Item() error = %v, want ErrNotFound", err)
+	}
+}
+
 func TestItemBadStatus(t *testing.T) {
 	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(t, w, map[string]any{
@@ -452,6 +464,28 @@ func TestPets(t *testing.T) {
 	}
 }
 
+func TestPet(t *testing.T) {
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/wow/pet/39" {
+			t.Errorf("path = %q, want /data/wow/pet/39", r.URL.Path)
+		}
+
+		writeJSON(t, w, map[string]any{
+			"id":          39,
+			"is_tradable": false,
+		})
+	}))
+
+	result, err := client.Pet(39)
+	if err != nil {
+		t.Fatalf("Pet() error = %v", err)
+	}
+
+	if result["is_tradable"] != false {
+		t.Errorf("is_tradable = %v, want false", result["is_tradable"])
+	}
+}
+
 func TestCollectionsPets(t *testing.T) {
 	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/profile/user/wow/collections/pets" {
@@ -502,6 +536,28 @@ func TestToys(t *testing.T) {
 	}
 }
 
+func TestToy(t *testing.T) {
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/wow/toy/100" {
+			t.Errorf("path = %q, want /data/wow/toy/100", r.URL.Path)
+		}
+
+		writeJSON(t, w, map[string]any{
+			"id":   100,
+			"item": map[string]any{"id": 12345},
+		})
+	}))
+
+	result, err := client.Toy(100)
+	if err != nil {
+		t.Fatalf("Toy() error = %v", err)
+	}
+
+	if _, ok := result["item"]; !ok {
+		t.Error("response is missing item")
+	}
+}
+
 func TestCollectionsToys(t *testing.T) {
 	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/profile/user/wow/collections/toys" {
@@ -525,6 +581,125 @@ func TestCollectionsToys(t *testing.T) {
 	}
 }
 
+func TestMounts(t *testing.T) {
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/wow/mount/index" {
+			t.Errorf("path = %q, want /data/wow/mount/index", r.URL.Path)
+		}
+
+		writeJSON(t, w, map[string]any{
+			"mounts": []any{
+				map[string]any{"id": 6},
+				map[string]any{"id": 7},
+			},
+		})
+	}))
+
+	result, err := client.Mounts()
+	if err != nil {
+		t.Fatalf("Mounts() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("len(result) = %d, want 2", len(result))
+	}
+}
+
+func TestCollectionsMounts(t *testing.T) {
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/profile/user/wow/collections/mounts" {
+			t.Errorf("path = %q, want collections mounts endpoint", r.URL.Path)
+		}
+
+		if got := r.Header.Get("Authorization"); got != "Bearer test-profile-access-token" {
+			t.Errorf("Authorization = %q, want profile token", got)
+		}
+
+		writeJSON(t, w, map[string]any{
+			"mounts": []any{
+				map[string]any{"mount": map[string]any{"id": 6}},
+			},
+		})
+	}))
+
+	result, err := client.CollectionsMounts()
+	if err != nil {
+		t.Fatalf("CollectionsMounts() error = %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("len(result) = %d, want 1", len(result))
+	}
+}
+
+func TestHeirlooms(t *testing.T) {
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/wow/heirloom/index" {
+			t.Errorf("path = %q, want /data/wow/heirloom/index", r.URL.Path)
+		}
+
+		writeJSON(t, w, map[string]any{
+			"heirlooms": []any{
+				map[string]any{"id": 1},
+			},
+		})
+	}))
+
+	result, err := client.Heirlooms()
+	if err != nil {
+		t.Fatalf("Heirlooms() error = %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("len(result) = %d, want 1", len(result))
+	}
+}
+
+func TestHeirloom(t *testing.T) {
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/wow/heirloom/1" {
+			t.Errorf("path = %q, want /data/wow/heirloom/1", r.URL.Path)
+		}
+
+		writeJSON(t, w, map[string]any{
+			"id":   1,
+			"item": map[string]any{"id": 122349},
+		})
+	}))
+
+	result, err := client.Heirloom(1)
+	if err != nil {
+		t.Fatalf("Heirloom() error = %v", err)
+	}
+
+	if _, ok := result["item"]; !ok {
+		t.Error("response is missing item")
+	}
+}
+
+func TestCollectionsHeirlooms(t *testing.T) {
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/profile/user/wow/collections/heirlooms" {
+			t.Errorf("path = %q, want collections heirlooms endpoint", r.URL.Path)
+		}
+
+		writeJSON(t, w, map[string]any{
+			"heirlooms": []any{
+				map[string]any{"heirloom": map[string]any{"id": 1}},
+			},
+		})
+	}))
+
+	result, err := client.CollectionsHeirlooms()
+	if err != nil {
+		t.Fatalf("CollectionsHeirlooms() error = %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("len(result) = %d, want 1", len(result))
+	}
+}
+
 func TestItemAppearanceSetsIndex(t *testing.T) {
 	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/data/wow/item-appearance/set/index" {
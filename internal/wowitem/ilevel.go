@@ -1,190 +1,287 @@
 package wowitem
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sync"
 )
 
-var itemLevels = map[int64][]int64{
-	// iLvl 70
-	223969: {0}, // Secret Sauce -- This does not appear to have distinct item levels
-
-	// iLvl 80
-	237946: {180, 186, 192, 199, 206}, // Thalassian Needle Set
-	237947: {180, 186, 192, 199, 206}, // Thalassian Leatherworker's Toolset
-	237948: {180, 186, 192, 199, 206}, // Thalassian Blacksmith's Toolbox
-	238009: {180, 186, 192, 199, 206}, // Thalassian Sickle
-	238010: {180, 186, 206},           // Thalassian Pickaxe
-	238011: {180, 186, 192, 199, 206}, // Thalassian Skinning Knife
-	238012: {180, 186, 192, 199, 206}, // Thalassian Leatherworker's Knife
-	238013: {180, 186, 192, 199, 206}, // Thalassian Blacksmith's Hammer
-	239641: {180, 186, 192, 199, 206}, // Bright Linen Alchemy Apron
-	239642: {180, 186, 192, 199, 206}, // Chef's Bright Linen Cooking Chapeau
-	239643: {180, 186, 192, 199, 206}, // Bright Linen Enchanting Hat
-	239645: {180, 186, 192, 199, 206}, // Bright Linen Herbalism Hat
-	239646: {180, 186, 192, 199, 206}, // Bright Linen Tailoring Robe
-	240953: {180, 186, 192, 199, 206}, // Bold Biographer's Bifocals
-	240954: {180, 186, 192, 199, 206}, // Fantastic Font Focuser
-	244175: {180, 186, 192, 199, 206}, // Runed Refulgent Copper Rod
-	244615: {180, 186, 192, 206},      // Eversong Botanist's Satchel
-	244617: {180, 186, 199, 206},      // Skinner's Cap (sell price: 2.50.00)
-	244618: {180, 186, 192, 199, 206}, // Tinker's Handguard
-	244619: {180, 186, 192, 199, 206}, // Hideworker's Cover
-	244627: {180, 186, 192, 199, 206}, // Apprentice Smith's Apron
-	244629: {180, 186, 192, 199, 206}, // Apprentice Jeweler's Apron
-	244713: {180, 186, 192, 199, 206}, // Farstrider Clampers
-	244717: {180, 186, 192, 199, 206}, // Junker's Multitool
-	245775: {180, 186, 192, 199, 206}, // Hobbyist Scribe's Quill
-	245777: {180, 186, 192, 199, 206}, // Hobbyist Alchemist's Mixing Rod
-	245779: {186, 192, 206},           // Hobbyist Rolling Pin
-
-	// iLvl 80 oddities
-	240955: {180, 183, 186, 189, 193}, // Silvermoon Loupes
-	240956: {180, 183, 186, 189, 193}, // Silvermoon Focusing Shard
-
-	// iLvl 106
-	237950: {218, 225, 232},           // Sun-Blessed Needle Set
-	237951: {225, 232},                // Sun-Blessed Leatherworker's Toolset
-	237952: {206, 212, 218, 225, 232}, // Sun-Blessed Blacksmith's Toolbox
-	238014: {218, 225, 232},           // Sun-Blessed Sickle
-	238015: {218, 225, 232},           // Sun-Blessed Pickaxe
-	238016: {218, 225, 232},           // Sun-Blessed Skinning Knife
-	238017: {212, 218, 225, 232},      // Sun-Blessed Leatherworker's Knife
-	238018: {206, 212, 218, 225, 232}, // Sun-Blessed Blacksmith's Hammer
-	239635: {212, 218, 225, 232},      // Elegant Artisan's Alchemy Coveralls
-	239636: {212, 218, 225, 232},      // Elegant Artisan's Cooking Hat
-	239637: {206, 212, 218, 225, 232}, // Elegant Artisan's Enchanting Hat
-	239639: {225, 232},                // Elegant Artisan's Herbalism Hat
-	239640: {218, 225, 232},           // Elegant Artisan's Tailoring Robe
-	240957: {212, 218, 225, 232},      // Sin'dorei Scribe's Spectacles
-	240958: {206, 212, 218, 225, 232}, // Improved Right-Handed Magnifying Glass
-	240959: {206, 212, 218, 225, 232}, // Sin'dorei Jeweler's Loupes
-	240960: {206, 212, 218, 225, 232}, // Sin'dorei Enchanter's Crystal
-	244176: {225, 232},                // Runed Brilliant Silver Rod
-	244621: {212, 232},                // Sin'dorei Herbalist's Backpack
-	244622: {225, 232},                // Sin'dorei Hunter's Pack
-	244623: {218, 225, 232},           // Eversong Hunter's Headcover
-	244624: {212, 218, 225, 232},      // Sin'dorei Engineer's Gloves
-	244625: {212, 225, 232},           // Sin'dorei Leathershaper's Smock
-	244628: {206, 212, 218, 225, 232}, // Sin'dorei Forgemaster's Cover
-	244630: {212, 218, 232},           // Sin'dorei Jeweler's Cover
-	244714: {212, 225, 232},           // Sin'dorei Clampers
-	244718: {206, 212, 218, 225, 232}, // Turbo-Junker's Multitool
-	245776: {206, 212, 218, 225, 232}, // Sin'dorei Quill
-	245778: {212, 218, 225, 232},      // Sin'dorei Alchemist's Mixing Rod
-	245780: {212, 218, 225, 232},      // Sin'dorei Rolling Pin
-
-	// iLvl 106 oddities
-	244616: {180, 186, 192, 199, 206}, // Skinner's Backpack
-
-	// iLvl 270
-	201601: {57, 58, 59, 61}, // Runed Serevite Rod
-
-	// iLvl 317
-	191233: {},                   // Chef's Smooth Rolling Pin
-	191234: {71},                 // Alchemist's Sturdy Mixing Rod
-	191235: {70, 71, 72, 73, 74}, // Draconium Blacksmith's Toolbox
-	191236: {71, 72, 73, 74},     // Draconium Leatherworker's Toolset
-	191237: {70, 71, 72, 73, 74}, // Draconium Blacksmith's Hammer
-	191238: {71, 72, 73, 74},     // Draconium Leatherworker's Knife
-	191239: {71, 72, 73, 74},     // Draconium Needle Set
-	191240: {70, 71, 72, 73, 74}, // Draconium Skinning Knife
-	191241: {70, 71, 72, 73, 74}, // Draconium Sickle
-	191242: {70, 71, 72, 73, 74}, // Draconium Pickaxe
-	193479: {74},                 // Floral Basket
-	193480: {71, 72, 73, 74},     // Durable Pack
-	193482: {74},                 // Skinner's Cap (sell price: 2.00.00)
-	193485: {72},                 // Protective Gloves
-	193486: {70, 71, 72, 73, 74}, // Resilient Smock
-	193487: {71, 72, 73, 74},     // Alchemist's Hat
-	193528: {72, 73, 74},         // Wildercloth Alchemist's Robe
-	193534: {74},                 // Wildercloth Chef's Hat
-	193538: {74},                 // Wildercloth Gardening Hat
-	193539: {},                   // Wildercloth Enchanter's Hat
-	193541: {70, 71, 72, 73, 74}, // Wildercloth Tailor's Coat
-	193612: {71, 72, 73, 74},     // Smithing Apron
-	193615: {71, 72, 73, 74},     // Jeweler's Cover
-	194125: {72},                 // Spring-Loaded Draconium Fabric Cutters
-	194874: {71, 72, 74},         // Scribe's Fastened Quill
-	198204: {72},                 // Draconium Brainwave Amplifier
-	198225: {74},                 // Draconium Fisherfriend
-	198234: {72, 74},             // Lapidary's Draconium Clamps
-	198243: {74},                 // Draconium Delver's Helmet
-	198245: {74},                 // Draconium Encased Samophlange
-	198262: {72, 74},             // Bottomless Stonecrust Ore Satchel
-	198715: {70, 71, 72, 73, 74}, // Runed Draconium Rod
-
-	// iLvl 317 oddities
-	224114: {79, 85, 91, 98, 105}, // Runed Bismuth Rod
-
-	// iLvl 486
-	215117: {},                    // Storyteller's Glasses
-	215119: {79, 85, 91, 98, 105}, // Right-Handed Magnifying Glass
-	215120: {79, 85, 91, 98, 105}, // Radiant Loupes
-	215121: {},                    // Incanter's Shard
-	219861: {},                    // Gardener's Basket
-	219862: {},                    // Hideseeker's Pack
-	219863: {},                    // Hideseeker's Hat
-	219864: {},                    // Scrapsmith's Gloves
-	219865: {},                    // Hideshaper's Cover
-	219866: {},                    // Apothecary's Cap
-	219873: {},                    // Steelsmith's Apron
-	219875: {},                    // Gemcutter's Apron
-	221786: {},                    // Spring-Loaded Bismuth Fabric Cutters
-	221788: {},                    // Bismuth Brainwave Projector
-	221790: {85, 91, 98, 105},     // Bismuth Fisherfriend
-	221792: {},                    // Lapidary's Bismuth Clamps
-	221795: {},                    // Bismuth Miner's Headgear
-	221797: {79, 85, 91, 98, 105}, // Bismuth-Fueled Samophlange
-	221799: {},                    // Miner's Bismuth Hoard
-	222480: {79, 85, 91, 98, 105}, // Proficient Sickle
-	222481: {},                    // Proficient Pickaxe
-	222482: {},                    // Proficient Skinning Knife
-	222483: {79, 85, 91, 98, 105}, // Proficient Needle Set
-	222484: {},                    // Proficient Leatherworker's Knife
-	222485: {},                    // Proficient Leatherworker's Toolset
-	222486: {},                    // Proficient Blacksmith's Hammer
-	222487: {},                    // Proficient Blacksmith's Toolbox
-	222573: {79, 85, 91, 98, 105}, // Lightweight Scribe's Quill
-	222575: {79, 85, 91, 98, 105}, // Hasty Alchemist's Mixing Rod
-	222577: {79, 85, 91, 98, 105}, // Burnt Rolling Pin
-	222841: {},                    // Weavercloth Gardening Hat
-	222843: {},                    // Weavercloth Enchanter's Hat
-	222844: {},                    // Weavercloth Tailor's Coat
-	222845: {},                    // Weavercloth Alchemist's Robe
-	244711: {},                    // Farstrider Hobbyist Rod
-	244715: {},                    // Farstrider Hardhat
-	244719: {},                    // Farstrider Rock Satchel
-
-	// iLvl 486 oddities
-	222846: {79, 80, 81, 82, 83},      // Weavercloth Chef's Hat
-	244620: {180, 186, 192, 199, 206}, // Chemist's Cap
-	244707: {180, 186, 192, 199, 206}, // Farstrider Fabric Cutters
-	244709: {180, 186, 192, 199, 206}, // Junker's Junk Visor
-
-	// iLvl 535
-	244626: {206, 212, 218, 225, 232}, // Sin'dorei Alchemist's Hat
-	244708: {206, 212, 218, 225, 232}, // Sin'dorei Snippers
-	244710: {218, 225, 232},           // Sin'dorei Headlamp
-	244712: {212, 225, 232},           // Sin'dorei Angler's Rod
-	244716: {225, 232},                // Sin'dorei Gilded Hardhat
-	244720: {225, 232},                // Junker's Big Ol' Bag
+// Tool is what we know about the iLevels of a profession tool. A tool whose only
+// iLevel is 0 does not have distinct iLevels; it is looked up without one.
+type Tool struct {
+	Name    string  `json:"name"`
+	Base    int64   `json:"base,omitempty"` // the iLevel the web API gives, once the tool has been seen for sale
+	ILevels []int64 `json:"ilevels"`
+
+	// Unresolved holds bonus lists seen for sale in which no bonus ID has a known iLevel adjustment
+	Unresolved [][]int64 `json:"unresolved,omitempty"`
+}
+
+// fixed returns true if the tool is marked as not having distinct iLevels
+func (t *Tool) fixed() bool {
+	return slices.Equal(t.ILevels, []int64{0})
+}
+
+// BonusRange is a run of bonus IDs that each add (ID - Zero) to an item's base iLevel
+type BonusRange struct {
+	First int64 `json:"first"`
+	Last  int64 `json:"last"`
+	Zero  int64 `json:"zero"`
+}
+
+// iLevelData is the layout of the iLevels data file
+type iLevelData struct {
+	// Bonuses maps each bonus ID that changes an item's iLevel to how much it adds to the base iLevel
+	Bonuses map[int64]int64 `json:"bonuses"`
+	// Ranges are runs of bonus IDs that each add a step to the base iLevel
+	Ranges []BonusRange `json:"ranges"`
+	// Qualities is how much each crafted quality, lowest first, adds to a crafted item's base iLevel
+	Qualities []int64         `json:"qualities"`
+	Tools     map[int64]*Tool `json:"tools"`
+}
+
+// ILevels holds the iLevels each profession tool has been seen for sale at, and the
+// bonus IDs that change an item's iLevel. Profession tools have to be looked up in the
+// auction house by itemID and iLevel, so we learn the iLevels from the auctions we scan.
+type ILevels struct {
+	filename string
+
+	mu    sync.RWMutex
+	data  iLevelData
+	dirty bool
+}
+
+// NewILevels loads the iLevels data file
+func NewILevels(filename string) (*ILevels, error) {
+	l := &ILevels{
+		filename: filename,
+		data: iLevelData{
+			Bonuses: map[int64]int64{},
+			Tools:   map[int64]*Tool{},
+		},
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read iLevels: %w", err)
+	}
+
+	err = json.Unmarshal(data, &l.data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	// Bonus IDs added since the last run may resolve bonus lists seen earlier
+	resolved := false
+	for _, tool := range l.data.Tools {
+		if tool.ILevels == nil {
+			tool.ILevels = []int64{}
+		}
+		lists := tool.Unresolved
+		iLevels := len(tool.ILevels)
+		tool.Unresolved = nil
+		for _, bonusList := range lists {
+			l.observe(tool, bonusList)
+		}
+		resolved = resolved || len(tool.Unresolved) != len(lists) || len(tool.ILevels) != iLevels
+	}
+	l.dirty = resolved
+
+	return l, nil
+}
+
+// Save writes the iLevels data file if anything was learned since it was loaded
+func (l *ILevels) Save() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(l.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	// Write to a temp file and rename so a failed write cannot truncate the data file
+	tmp := filepath.Join(filepath.Dir(l.filename), "."+filepath.Base(l.filename)+".tmp")
+	err = os.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to save iLevels: %w", err)
+	}
+
+	err = os.Rename(tmp, l.filename)
+	if err != nil {
+		return fmt.Errorf("failed to save iLevels: %w", err)
+	}
+
+	l.dirty = false
+
+	return nil
 }
 
 // Known returns true if we have item level data for this itemID
-func Known(itemID int64) bool {
-	_, ok := itemLevels[itemID]
+func (l *ILevels) Known(itemID int64) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	_, ok := l.data.Tools[itemID]
 	return ok
 }
 
 // ILevels returns the item levels for this itemID
-func ILevels(itemID int64) []int64 {
-	iLevels, ok := itemLevels[itemID]
+func (l *ILevels) ILevels(itemID int64) []int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	tool, ok := l.data.Tools[itemID]
 	if !ok {
 		return []int64{0}
 	}
-	if len(iLevels) == 0 {
+	if len(tool.ILevels) == 0 {
 		fmt.Fprintf(os.Stderr, "*** missing item levels for: %d\n", itemID)
 	}
-	return iLevels
+	return slices.Clone(tool.ILevels)
+}
+
+// Unresolved returns the bonus lists seen for this itemID that have no known iLevel
+func (l *ILevels) Unresolved(itemID int64) [][]int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	tool, ok := l.data.Tools[itemID]
+	if !ok {
+		return nil
+	}
+	return slices.Clone(tool.Unresolved)
+}
+
+// adjustment returns how much the bonus list adds to the base iLevel, and false if
+// none of its bonus IDs is known to change the iLevel. The caller must hold the lock.
+func (l *ILevels) adjustment(bonusList []int64) (int64, bool) {
+	adjust := int64(0)
+	known := false
+
+	for _, bonusID := range bonusList {
+		if step, ok := l.data.Bonuses[bonusID]; ok {
+			adjust += step
+			known = true
+			continue
+		}
+		for _, r := range l.data.Ranges {
+			if bonusID >= r.First && bonusID <= r.Last {
+				adjust += bonusID - r.Zero
+				known = true
+				break
+			}
+		}
+	}
+
+	return adjust, known
+}
+
+// Effective returns the iLevel of an item listed with this bonus list, or 0 if it is not known.
+// An item with no bonuses, or a tool without distinct iLevels, is at its base iLevel.
+func (l *ILevels) Effective(item Item, bonusList []int64) int64 {
+	base := item.ItemLevel()
+	if len(bonusList) == 0 {
+		return base
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if tool, ok := l.data.Tools[item.ID()]; ok && tool.fixed() {
+		return base
+	}
+
+	adjust, ok := l.adjustment(bonusList)
+	if !ok || base <= 0 {
+		return 0
+	}
+
+	return base + adjust
+}
+
+// Observe records that a profession tool was listed for sale with this bonus list. It
+// returns true if the bonus list could not be resolved to an iLevel and was not seen
+// before, so it is reported only once.
+func (l *ILevels) Observe(item Item, bonusList []int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tool, ok := l.data.Tools[item.ID()]
+	if !ok {
+		tool = &Tool{Name: item.Name(), ILevels: []int64{}}
+		l.data.Tools[item.ID()] = tool
+		l.dirty = true
+	}
+
+	if tool.fixed() {
+		// Its bonuses do not change how it is looked up, so there is nothing to learn
+		return false
+	}
+
+	if base := item.ItemLevel(); base > 0 && tool.Base != base {
+		tool.Base = base
+		l.dirty = true
+	}
+
+	return l.observe(tool, bonusList)
+}
+
+// observe records the iLevel a bonus list gives tool, or the bonus list itself if it
+// cannot be resolved. It returns true if it recorded a new unresolved bonus list. The
+// caller must hold the lock or own l exclusively.
+func (l *ILevels) observe(tool *Tool, bonusList []int64) bool {
+	if tool.Base <= 0 {
+		// Without the base iLevel there is nothing to add the bonuses to
+		if len(bonusList) == 0 {
+			return false
+		}
+		return l.unresolved(tool, bonusList)
+	}
+
+	adjust, ok := l.adjustment(bonusList)
+	if ok || len(bonusList) == 0 {
+		// Not crafted, or the bonuses say by how much; either way there is only the one iLevel
+		l.addILevel(tool, tool.Base+adjust)
+		return false
+	}
+
+	// A crafted tool comes in one iLevel per quality; until its quality bonus is known,
+	// a tool seen for the first time is assumed to come in every quality
+	if len(tool.ILevels) == 0 {
+		for _, step := range l.data.Qualities {
+			l.addILevel(tool, tool.Base+step)
+		}
+	}
+
+	return l.unresolved(tool, bonusList)
+}
+
+// addILevel adds iLevel to the tool's iLevels if it is new
+func (l *ILevels) addILevel(tool *Tool, iLevel int64) {
+	if slices.Contains(tool.ILevels, iLevel) {
+		return
+	}
+	tool.ILevels = append(tool.ILevels, iLevel)
+	slices.Sort(tool.ILevels)
+	l.dirty = true
+}
+
+// unresolved records bonusList as unresolved for tool, returning true if it is new
+func (l *ILevels) unresolved(tool *Tool, bonusList []int64) bool {
+	bonusList = slices.Sorted(slices.Values(bonusList))
+	if slices.ContainsFunc(tool.Unresolved, func(b []int64) bool { return slices.Equal(b, bonusList) }) {
+		return false
+	}
+	tool.Unresolved = append(tool.Unresolved, bonusList)
+	l.dirty = true
+	return true
 }
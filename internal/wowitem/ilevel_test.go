@@ -1,22 +1,174 @@
 package wowitem
 
-import "testing"
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func writeILevels(t *testing.T, data string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "ilevels.json")
+	if err := os.WriteFile(filename, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
 
 func TestILevels(t *testing.T) {
-	if !Known(237946) || Known(999999999) {
+	l, err := NewILevels(filepath.Join("..", "..", "data", "ilevels.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !l.Known(237946) || l.Known(999999999) {
 		t.Fail()
 	}
-	got := ILevels(237946)
+	got := l.ILevels(237946)
 	want := []int64{180, 186, 192, 199, 206}
-	if len(got) != len(want) {
+	if !slices.Equal(got, want) {
 		t.Fatalf("%v", got)
 	}
-	for i := range want {
-		if got[i] != want[i] {
-			t.Fatalf("%v", got)
+	if got := l.ILevels(999999999); len(got) != 1 || got[0] != 0 {
+		t.Fatalf("unknown=%v", got)
+	}
+}
+
+// tool returns a profession tool as the web API describes it, at its base iLevel
+func tool(id int64, name string, base int64) Item {
+	return Item{XID: id, XItem: map[string]any{"name": name, "level": json.Number(strconv.FormatInt(base, 10))}}
+}
+
+func TestILevelsObserve(t *testing.T) {
+	filename := writeILevels(t, `{"bonuses": {}, "ranges": [{"first": 1372, "last": 1672, "zero": 1472}], "qualities": [0, 6, 12, 19, 26], "tools": {"1": {"name": "Old Pick", "ilevels": [180]}}}`)
+
+	l, err := NewILevels(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pick := tool(1, "Old Pick", 180)
+	needles := tool(2, "Thalassian Needle Set", 180)
+
+	// An auction whose bonus list raises the iLevel teaches a new iLevel
+	if l.Observe(pick, []int64{6652, 1478}) {
+		t.Error("bonus 1478 (+6) should resolve")
+	}
+	if got := l.ILevels(1); !slices.Equal(got, []int64{180, 186}) {
+		t.Errorf("pick iLevels = %v", got)
+	}
+
+	// A crafted tool seen for the first time with an unknown quality bonus comes in every quality
+	if !l.Observe(needles, []int64{12240, 10222}) {
+		t.Error("an unknown bonus list should be reported the first time")
+	}
+	if got := l.ILevels(2); !slices.Equal(got, []int64{180, 186, 192, 199, 206}) {
+		t.Errorf("needles iLevels = %v", got)
+	}
+	if l.Observe(needles, []int64{10222, 12240}) {
+		t.Error("the same bonus list should be reported only once")
+	}
+	if got := l.Unresolved(2); len(got) != 1 || !slices.Equal(got[0], []int64{10222, 12240}) {
+		t.Errorf("needles unresolved = %v", got)
+	}
+
+	if err := l.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Learning the quality bonus resolves the earlier sighting on the next load
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved iLevelData
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Tools[2].Base != 180 {
+		t.Errorf("saved base = %d", saved.Tools[2].Base)
+	}
+	saved.Bonuses[12240] = 26
+	data, err = json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = NewILevels(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := l.ILevels(1); !slices.Equal(got, []int64{180, 186}) {
+		t.Errorf("reloaded pick iLevels = %v", got)
+	}
+	if got := l.ILevels(2); !slices.Equal(got, []int64{180, 186, 192, 199, 206}) || len(l.Unresolved(2)) != 0 {
+		t.Errorf("reloaded needles iLevels = %v unresolved = %v", got, l.Unresolved(2))
+	}
+}
+
+func TestILevelsObserveFixed(t *testing.T) {
+	filename := writeILevels(t, `{"bonuses": {}, "ranges": [{"first": 1372, "last": 1672, "zero": 1472}], "qualities": [0, 6, 12, 19, 26], "tools": {"223969": {"name": "Secret Sauce", "ilevels": [0]}}}`)
+
+	l, err := NewILevels(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A tool marked as having no distinct iLevels keeps its explicit 0
+	sauce := tool(223969, "Secret Sauce", 70)
+	for _, bonusList := range [][]int64{nil, {1478}, {12240}} {
+		if l.Observe(sauce, bonusList) {
+			t.Errorf("Observe(%v) reported a fixed tool", bonusList)
 		}
 	}
-	if got := ILevels(999999999); len(got) != 1 || got[0] != 0 {
-		t.Fatalf("unknown=%v", got)
+	if got := l.ILevels(223969); !slices.Equal(got, []int64{0}) {
+		t.Errorf("Secret Sauce iLevels = %v, want [0]", got)
+	}
+	if len(l.Unresolved(223969)) != 0 || l.dirty {
+		t.Error("nothing should be learned about a fixed tool")
+	}
+	if got := l.Effective(sauce, []int64{1478}); got != 70 {
+		t.Errorf("Effective = %d, want the base iLevel 70", got)
+	}
+}
+
+func TestILevelsEffective(t *testing.T) {
+	l, err := NewILevels(writeILevels(t, `{"bonuses": {"100": 6}, "ranges": [{"first": 1372, "last": 1672, "zero": 1472}], "tools": {}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := tool(1, "Sword", 170)
+
+	for _, tt := range []struct {
+		bonusList []int64
+		want      int64
+	}{
+		{nil, 170},
+		{[]int64{7, 100}, 176},
+		{[]int64{6652, 1482}, 180},
+		{[]int64{1462}, 160},
+		{[]int64{100, 1477}, 181},
+		{[]int64{7}, 0},
+	} {
+		if got := l.Effective(item, tt.bonusList); got != tt.want {
+			t.Errorf("Effective(%v) = %d, want %d", tt.bonusList, got, tt.want)
+		}
+	}
+}
+
+func TestILevelsInvalid(t *testing.T) {
+	if _, err := NewILevels(writeILevels(t, `{"tools": [`)); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := NewILevels(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
 	}
 }
@@ -0,0 +1,213 @@
+package wowitem
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Scores for how well a query word matched a word in an item name
+const (
+	scoreFuzzy  = 1
+	scorePrefix = 2
+	scoreWord   = 3
+
+	// Bonuses for how well the whole query matched the whole name
+	scoreNamePrefix = 5
+	scoreNameExact  = 10
+)
+
+// Match is an item found by a name search
+type Match struct {
+	ID    int64
+	Name  string
+	Score int
+}
+
+// NameIndex is an inverted index of item names
+type NameIndex struct {
+	names  map[int64]string   // item ID to name
+	exact  map[string][]int64 // lowercased full name to item IDs
+	byWord map[string][]int64 // lowercased name word to item IDs
+	words  []string           // every indexed word, sorted, for prefix search
+}
+
+// tokenize splits a name into lowercased words
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+// NewNameIndex indexes the names of the given items.
+func NewNameIndex(items []Item) *NameIndex {
+	ni := &NameIndex{
+		names:  map[int64]string{},
+		exact:  map[string][]int64{},
+		byWord: map[string][]int64{},
+	}
+
+	for _, item := range items {
+		name := item.Name()
+		ni.names[item.ID()] = name
+
+		lower := strings.ToLower(name)
+		ni.exact[lower] = append(ni.exact[lower], item.ID())
+
+		for _, word := range tokenize(name) {
+			ids := ni.byWord[word]
+			if len(ids) > 0 && ids[len(ids)-1] == item.ID() {
+				// Repeated word in the same name
+				continue
+			}
+			ni.byWord[word] = append(ids, item.ID())
+		}
+	}
+
+	for _, ids := range ni.exact {
+		slices.Sort(ids)
+	}
+	for word, ids := range ni.byWord {
+		slices.Sort(ids)
+		ni.byWord[word] = slices.Compact(ids)
+		ni.words = append(ni.words, word)
+	}
+	slices.Sort(ni.words)
+
+	return ni
+}
+
+// Exact returns the IDs of every item whose name is s, ignoring case, lowest ID first.
+func (ni *NameIndex) Exact(s string) []int64 {
+	return slices.Clone(ni.exact[strings.ToLower(s)])
+}
+
+// maxEdits returns how many typos a query word of this length may contain
+func maxEdits(word string) int {
+	switch n := len([]rune(word)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// editDistance returns the Levenshtein distance between a and b, or a number
+// larger than limit once it is clear the distance exceeds limit
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// wordMatches returns the best score of every item with a name word matching this query word
+func (ni *NameIndex) wordMatches(word string) map[int64]int {
+	scores := map[int64]int{}
+	add := func(w string, score int) {
+		for _, id := range ni.byWord[w] {
+			scores[id] = max(scores[id], score)
+		}
+	}
+
+	// Words sharing the prefix are contiguous in the sorted word list
+	start, _ := slices.BinarySearch(ni.words, word)
+	for _, w := range ni.words[start:] {
+		if !strings.HasPrefix(w, word) {
+			break
+		}
+		if w == word {
+			add(w, scoreWord)
+		} else {
+			add(w, scorePrefix)
+		}
+	}
+
+	if limit := maxEdits(word); limit > 0 {
+		for _, w := range ni.words {
+			if !strings.HasPrefix(w, word) && editDistance(word, w, limit) <= limit {
+				add(w, scoreFuzzy)
+			}
+		}
+	}
+
+	return scores
+}
+
+// Search returns the items whose names match every word in query, best match first.
+// Each query word matches a whole name word, the start of one, or one with a typo or two.
+func (ni *NameIndex) Search(query string) []Match {
+	words := tokenize(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var scores map[int64]int
+	for _, word := range words {
+		matches := ni.wordMatches(word)
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for id, score := range scores {
+			if wordScore, ok := matches[id]; ok {
+				scores[id] = score + wordScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	lowerQuery := strings.ToLower(strings.TrimSpace(query))
+	results := []Match{}
+	for id, score := range scores {
+		name := ni.names[id]
+		lowerName := strings.ToLower(name)
+		switch {
+		case lowerName == lowerQuery:
+			score += scoreNameExact
+		case strings.HasPrefix(lowerName, lowerQuery):
+			score += scoreNamePrefix
+		}
+		results = append(results, Match{ID: id, Name: name, Score: score})
+	}
+
+	slices.SortFunc(results, func(a, b Match) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(len(a.Name), len(b.Name)),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	return results
+}
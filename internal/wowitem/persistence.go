@@ -1,10 +1,12 @@
 package wowitem
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"slices"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/erikbryant/wow/internal/persist"
 	"github.com/erikbryant/wow/internal/wowapi"
@@ -12,6 +14,10 @@ import (
 
 type Persistence struct {
 	*persist.Persistence[int64, Item]
+
+	// names is built on first search and dropped whenever the items change
+	mu    sync.Mutex
+	names *NameIndex
 }
 
 // NewEmpty creates a new Persistence with no items in it.
@@ -34,32 +40,165 @@ func New(persistencePath string) (*Persistence, error) {
 	return p, nil
 }
 
-// Search returns the first item with name s.
-// Duplicates are very rare.
-func (p *Persistence) Search(s string) *Item {
-	_, item, ok := p.Persistence.Search(func(v Item) bool {
-		return v.Name() == s
-	})
-	if !ok {
-		fmt.Fprintf(os.Stderr, "*** did not find item for search string: %s\n", s)
+// Open creates a new Persistence populated from exactly filename (e.g. a backup such as items.gob.bak).
+func Open(filename string) (*Persistence, error) {
+	p := &Persistence{
+		Persistence: persist.NewFile[int64, Item](filename),
+	}
+
+	if err := p.Load(); err != nil {
+		return nil, fmt.Errorf("error loading items persist %s: %w", filename, err)
+	}
+
+	return p, nil
+}
+
+// Names returns the name index, building it if the items changed since it was last built.
+func (p *Persistence) Names() *NameIndex {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.names == nil {
+		p.names = NewNameIndex(p.Values())
+	}
+
+	return p.names
+}
+
+// invalidateNames drops the name index so the next search rebuilds it
+func (p *Persistence) invalidateNames() {
+	p.mu.Lock()
+	p.names = nil
+	p.mu.Unlock()
+}
+
+// Load replaces the current data with the contents of the persistence file.
+func (p *Persistence) Load() error {
+	defer p.invalidateNames()
+	return p.Persistence.Load()
+}
+
+// Set stores an item and marks the persistence dirty.
+func (p *Persistence) Set(id int64, item Item) {
+	p.Persistence.Set(id, item)
+	p.invalidateNames()
+}
+
+// Delete removes an item, if present, and marks the persistence dirty.
+func (p *Persistence) Delete(id int64) {
+	p.Persistence.Delete(id)
+	p.invalidateNames()
+}
+
+var (
+	// ErrNotFound means no item has the requested name
+	ErrNotFound = errors.New("item not found")
+
+	// ErrAmbiguous means more than one item has the requested name
+	ErrAmbiguous = errors.New("item name is ambiguous")
+)
+
+// Disambiguation narrows a name lookup when several items share a name.
+// Zero-valued fields do not filter.
+type Disambiguation struct {
+	Class   string
+	ILevel  int64
+	Quality string
+}
+
+// matches returns true if the item satisfies every non-zero field
+func (d Disambiguation) matches(i Item) bool {
+	if d.Class != "" && i.ItemClassName() != d.Class {
+		return false
+	}
+	if d.ILevel != 0 && i.ItemLevel() != d.ILevel {
+		return false
+	}
+	if d.Quality != "" && i.Quality() != d.Quality {
+		return false
+	}
+	return true
+}
+
+// Lookup returns every item named s, ignoring case, lowest ID first.
+func (p *Persistence) Lookup(s string) ([]Item, error) {
+	items := []Item{}
+
+	for _, id := range p.Names().Exact(s) {
+		item, ok := p.Persistence.Get(id)
+		if ok {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%q: %w", s, ErrNotFound)
+	}
+
+	return items, nil
+}
+
+// LookupOne returns the single item named s that satisfies d. It is an error
+// if no item or more than one item qualifies.
+func (p *Persistence) LookupOne(s string, d Disambiguation) (Item, error) {
+	items, err := p.Lookup(s)
+	if err != nil {
+		return Item{}, err
+	}
+
+	items = slices.DeleteFunc(items, func(i Item) bool { return !d.matches(i) })
+
+	switch len(items) {
+	case 0:
+		return Item{}, fmt.Errorf("%q with %+v: %w", s, d, ErrNotFound)
+	case 1:
+		return items[0], nil
+	default:
+		candidates := []string{}
+		for _, i := range items {
+			candidates = append(candidates, fmt.Sprintf("%d (%s, iLvl %d, %s)", i.ID(), i.ItemClassName(), i.ItemLevel(), i.Quality()))
+		}
+		return Item{}, fmt.Errorf("%q matches %s: %w", s, strings.Join(candidates, ", "), ErrAmbiguous)
 	}
+}
 
-	return &item
+// SearchNames returns the items whose names best match query, best match first.
+func (p *Persistence) SearchNames(query string) []Match {
+	return p.Names().Search(query)
+}
+
+// Fetch retrieves a single item from the WoW web API without persisting it.
+func Fetch(id int64) (Item, error) {
+	result, err := wowapi.Item(strconv.FormatInt(id, 10))
+	if err != nil {
+		return Item{}, err
+	}
+
+	item, err := NewItem(result)
+	if err != nil {
+		return Item{}, fmt.Errorf("item %d: %w", id, err)
+	}
+
+	err = item.Validate()
+	if err != nil {
+		return Item{}, err
+	}
+
+	return *item, nil
 }
 
 // GetLive retrieves a single item from the WoW web API and persists it.
 func (p *Persistence) GetLive(id int64) (Item, error) {
-	result, err := wowapi.Item(strconv.FormatInt(id, 10))
+	item, err := Fetch(id)
 	if err != nil {
 		return Item{}, err
 	}
 
 	fmt.Println("Downloaded new item:", id)
 
-	item := NewItem(result)
-	p.Set(item.ID(), *item)
+	p.Set(item.ID(), item)
 
-	return *item, nil
+	return item, nil
 }
 
 // Get retrieves a single item. From persistence if present, web if not.
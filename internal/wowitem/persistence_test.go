@@ -2,20 +2,157 @@ package wowitem
 
 import (
 	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
 	"testing"
 )
 
-func TestPersistenceSearchAndSortedKeys(t *testing.T) {
+func TestPersistenceLookupAndSortedKeys(t *testing.T) {
 	p := NewEmpty(t.TempDir() + "/items")
-	p.Set(20, *NewItem(map[string]any{"id": json.Number("20"), "name": "Beta"}))
-	p.Set(10, *NewItem(map[string]any{"id": json.Number("10"), "name": "Alpha"}))
+	p.Set(20, *testItem(map[string]any{"id": json.Number("20"), "name": "Beta"}))
+	p.Set(10, *testItem(map[string]any{"id": json.Number("10"), "name": "Alpha"}))
 	if got := p.Keys(); len(got) != 2 || got[0] != 10 || got[1] != 20 {
 		t.Fatalf("keys=%v", got)
 	}
-	if got := p.Search("Beta"); got.ID() != 20 {
-		t.Fatalf("search=%d", got.ID())
+	if got, err := p.Lookup("Beta"); err != nil || len(got) != 1 || got[0].ID() != 20 {
+		t.Fatalf("lookup=%v, %v", got, err)
 	}
-	if got := p.Search("Missing"); got.ID() != 0 {
-		t.Fatalf("missing search=%d", got.ID())
+	if _, err := p.Lookup("Missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing lookup error=%v", err)
+	}
+}
+
+func lookupItem(id int64, name, class, quality string, level int64) Item {
+	return *testItem(map[string]any{
+		"id":           json.Number(strconv.FormatInt(id, 10)),
+		"name":         name,
+		"level":        json.Number(strconv.FormatInt(level, 10)),
+		"item_class":   map[string]any{"name": class},
+		"preview_item": map[string]any{"quality": map[string]any{"name": quality}},
+	})
+}
+
+func TestPersistenceLookupOne(t *testing.T) {
+	p := NewEmpty(t.TempDir() + "/items")
+	p.Set(1, lookupItem(1, "Blackfury", "Weapon", "Epic", 60))
+	p.Set(2, lookupItem(2, "Blackfury", "Weapon", "Rare", 60))
+	p.Set(3, lookupItem(3, "Blackfury", "Recipe", "Rare", 1))
+	p.Set(4, lookupItem(4, "Kickback 5000", "Weapon", "Rare", 60))
+
+	all, err := p.Lookup("blackfury")
+	if err != nil || len(all) != 3 || all[0].ID() != 1 || all[2].ID() != 3 {
+		t.Fatalf("Lookup() = %v, %v", all, err)
+	}
+
+	tests := []struct {
+		name    string
+		item    string
+		d       Disambiguation
+		want    int64
+		wantErr error
+	}{
+		{"unique name", "Kickback 5000", Disambiguation{}, 4, nil},
+		{"ambiguous", "Blackfury", Disambiguation{}, 0, ErrAmbiguous},
+		{"by class", "Blackfury", Disambiguation{Class: "Recipe"}, 3, nil},
+		{"by quality", "Blackfury", Disambiguation{Class: "Weapon", Quality: "Epic"}, 1, nil},
+		{"by ilevel", "Blackfury", Disambiguation{ILevel: 1}, 3, nil},
+		{"filtered away", "Blackfury", Disambiguation{Class: "Armor"}, 0, ErrNotFound},
+		{"missing", "Missing", Disambiguation{}, 0, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.LookupOne(tt.item, tt.d)
+			if !errors.Is(err, tt.wantErr) || got.ID() != tt.want {
+				t.Fatalf("LookupOne(%q, %+v) = %d, %v, want %d, %v", tt.item, tt.d, got.ID(), err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func namedItem(id int64, name string) Item {
+	return *testItem(map[string]any{"id": json.Number(strconv.FormatInt(id, 10)), "name": name})
+}
+
+func TestNameIndexSearch(t *testing.T) {
+	ni := NewNameIndex([]Item{
+		namedItem(1, "Tyrhold Broadsword"),
+		namedItem(2, "Tyrhold Visage"),
+		namedItem(3, "Broadsword of the Tyrhold"),
+		namedItem(4, "Kickback 5000"),
+		namedItem(5, "Tyrhold Broadsword"),
+		namedItem(6, "Ameelton's Shot-Thrower"),
+	})
+
+	matchIDs := func(matches []Match) []int64 {
+		ids := []int64{}
+		for _, m := range matches {
+			ids = append(ids, m.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		query string
+		want  []int64
+	}{
+		{"Tyrhold Broadsword", []int64{1, 5, 3}},
+		{"tyr broad", []int64{1, 5, 3}},
+		{"tyrhold", []int64{2, 1, 5, 3}},
+		{"tyrhold brodsword", []int64{1, 5, 3}},
+		{"kickback", []int64{4}},
+		{"ameelton's", []int64{6}},
+		{"visage broadsword", []int64{}},
+		{"", []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := matchIDs(ni.Search(tt.query)); !slices.Equal(got, tt.want) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if got := ni.Exact("tyrhold broadsword"); !slices.Equal(got, []int64{1, 5}) {
+		t.Fatalf("Exact() = %v, want [1 5]", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"broadsword", "broadsword", 2, 0},
+		{"brodsword", "broadsword", 2, 1},
+		{"visage", "vsiage", 2, 2},
+		{"kickback", "tyrhold", 2, 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestPersistenceSearchNamesAfterSet(t *testing.T) {
+	p := NewEmpty(t.TempDir() + "/items")
+	p.Set(1, namedItem(1, "Alpha"))
+	if got := p.SearchNames("alpha"); len(got) != 1 {
+		t.Fatalf("SearchNames() = %v", got)
+	}
+
+	// Changing the items must rebuild the index
+	p.Set(2, namedItem(2, "Alphabet"))
+	if got := p.SearchNames("alpha"); len(got) != 2 || got[0].ID != 1 {
+		t.Fatalf("SearchNames() after Set = %v", got)
+	}
+	p.Delete(1)
+	if got := p.SearchNames("alpha"); len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("SearchNames() after Delete = %v", got)
 	}
 }
@@ -0,0 +1,82 @@
+package wowitem
+
+// Confidence says how far a sell price can be trusted
+type Confidence int
+
+const (
+	// ConfidenceNone means there is no usable sell price
+	ConfidenceNone Confidence = iota
+	// ConfidenceEstimated means the price was derived and has not been checked against the game
+	ConfidenceEstimated
+	// ConfidenceValidated means the price is the one the game pays
+	ConfidenceValidated
+)
+
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceEstimated:
+		return "estimated"
+	case ConfidenceValidated:
+		return "validated"
+	default:
+		return "none"
+	}
+}
+
+// SellPrice is what a vendor pays for an item, and how sure we are of it
+type SellPrice struct {
+	Value      int64
+	Confidence Confidence
+	Rule       string // the pricing rule that produced it
+}
+
+// Realizable returns the price if it can be relied on, otherwise 0
+func (p SellPrice) Realizable() int64 {
+	if p.Confidence != ConfidenceValidated {
+		return 0
+	}
+	return p.Value
+}
+
+// SellPriceAt returns the vendor sell price of the item at an effective iLevel.
+// An iLevel of 0 means the effective iLevel is not known. A price at any iLevel
+// other than the base is estimated with an unverified heuristic: the advertised
+// price scaled linearly by iLevel / base iLevel.
+func (i *Item) SellPriceAt(iLevel int64) SellPrice {
+	advertised := i.SellPriceAdvertised()
+	base := i.ItemLevel()
+
+	switch {
+	case advertised == 0:
+		return SellPrice{Rule: "no sell price"}
+	case !i.VariableItemLevel():
+		return SellPrice{Value: advertised, Confidence: ConfidenceValidated, Rule: "fixed iLevel"}
+	case iLevel == base:
+		// The web API describes the item at its base iLevel, and /merch validate checks this price
+		return SellPrice{Value: advertised, Confidence: ConfidenceValidated, Rule: "base iLevel"}
+	case iLevel <= 0 || base <= 0:
+		return SellPrice{Rule: "unknown iLevel"}
+	default:
+		// A heuristic: vendor prices grow with iLevel, but we have no evidence that they
+		// grow in proportion to it. This is a first guess to check in game.
+		return SellPrice{Value: advertised * iLevel / base, Confidence: ConfidenceEstimated, Rule: "scaled from base iLevel"}
+	}
+}
+
+// SellPriceListed returns the vendor sell price of the item in an auction with this bonus
+// list, at the effective iLevel the bonuses give it. /merch validate only checks the price
+// of an item with no bonuses, so for an item whose iLevel can change any bonus makes the
+// price an estimate, even one that leaves the iLevel at its base.
+func (i *Item) SellPriceListed(bonusList []int64, iLevel int64) SellPrice {
+	if len(bonusList) == 0 {
+		return i.SellPriceAt(i.ItemLevel())
+	}
+
+	price := i.SellPriceAt(iLevel)
+	if price.Confidence == ConfidenceValidated && i.VariableItemLevel() {
+		price.Confidence = ConfidenceEstimated
+		price.Rule = "bonuses at base iLevel"
+	}
+
+	return price
+}
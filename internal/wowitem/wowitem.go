@@ -1,7 +1,9 @@
 package wowitem
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/erikbryant/web"
@@ -42,12 +44,73 @@ var equipSlotTypes = map[string]struct{}{
 }
 
 // NewItem returns an Item populated with wowData
-func NewItem(wowData map[string]any) *Item {
+func NewItem(wowData map[string]any) (*Item, error) {
+	id, err := common.JSONInt64(wowData["id"])
+	if err != nil {
+		return nil, fmt.Errorf("item id: %w", err)
+	}
+
 	return &Item{
-		XID:      common.JSONInt64Panic(wowData["id"]),
+		XID:      id,
 		XItem:    wowData,
 		XUpdated: time.Now(),
+	}, nil
+}
+
+// optionalStrings are the keys of the string fields the web API only sometimes gives
+var optionalStrings = [][]string{
+	{"preview_item", "binding", "type"},
+	{"item_subclass", "name"},
+	{"preview_item", "gem_properties", "relic_type"},
+}
+
+// Validate returns an error naming each field the accessors need that is missing or malformed.
+// The accessors return zero values for those fields, so check items from the web API with this first.
+func (i *Item) Validate() error {
+	var errs []error
+
+	v, err := web.MsiValue(i.XItem, []string{"level"})
+	if err == nil {
+		_, err = common.JSONInt64(v)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("level: %w", err))
+	}
+
+	v, err = web.MsiValue(i.XItem, []string{"item_class", "name"})
+	if _, ok := v.(string); err == nil && !ok {
+		err = fmt.Errorf("expected string, got %T", v)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("item_class: %w", err))
+	}
+
+	v, err = web.MsiValue(i.XItem, []string{"is_stackable"})
+	if _, ok := v.(bool); err == nil && !ok {
+		err = fmt.Errorf("expected bool, got %T", v)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("is_stackable: %w", err))
+	}
+
+	if _, ok := i.XItem["name"].(string); !ok {
+		errs = append(errs, fmt.Errorf("name: expected string, got %T", i.XItem["name"]))
 	}
+
+	// These are only sometimes there, but must be strings when they are
+	for _, keys := range optionalStrings {
+		v, _ = web.MsiValued(i.XItem, keys, "")
+		if _, ok := v.(string); !ok {
+			errs = append(errs, fmt.Errorf("%s: expected string, got %T", strings.Join(keys, "."), v))
+		}
+	}
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return fmt.Errorf("item %d: %w", i.ID(), err)
+	}
+
+	return nil
 }
 
 // ID returns the item ID
@@ -55,18 +118,25 @@ func (i *Item) ID() int64 {
 	return i.XID
 }
 
-// Binding returns whether and when the item binds
+// Binding returns whether and when the item binds, or "" if it is missing (see Validate)
 func (i *Item) Binding() string {
 	// The key is only sometimes there; do not error if it is missing
 	value, _ := web.MsiValued(i.XItem, []string{"preview_item", "binding", "type"}, "")
-	return value.(string)
+	binding, _ := value.(string)
+	return binding
 }
 
 // InventoryType returns the slot this item equips to, or UNKNOWN
 func (i *Item) InventoryType() string {
 	// The key is only sometimes there; do not error if it is missing
 	value, _ := web.MsiValued(i.XItem, []string{"inventory_type"}, "UNKNOWN")
-	return value.(string)
+
+	// The web API returns {"type": "HEAD", "name": "Head"}; synthetic items store just the type
+	if _, ok := value.(map[string]any); ok {
+		value, _ = web.MsiValued(value, []string{"type"}, "UNKNOWN")
+	}
+
+	return common.JSONString(value)
 }
 
 // Equippable returns true if the item is equippable
@@ -83,13 +153,11 @@ func (i *Item) Equippable() bool {
 	return isEquipSlot
 }
 
-// ItemLevel returns the item level
+// ItemLevel returns the item level, or 0 if it is missing (see Validate)
 func (i *Item) ItemLevel() int64 {
-	v, err := web.MsiValue(i.XItem, []string{"level"})
-	if err != nil {
-		panic(fmt.Errorf("level missing from %v: %w", i.XItem, err))
-	}
-	return common.JSONInt64Panic(v)
+	v, _ := web.MsiValued(i.XItem, []string{"level"}, nil)
+	level, _ := common.JSONInt64(v)
+	return level
 }
 
 // VariableItemLevel returns true if the item can be enhanced, changing its ilevel
@@ -101,10 +169,11 @@ func (i *Item) VariableItemLevel() bool {
 	return cn == "Armor" || cn == "Gem" || cn == "Weapon"
 }
 
-// ItemSubclassName returns the item subclass name
+// ItemSubclassName returns the item subclass name, or "" if it is missing (see Validate)
 func (i *Item) ItemSubclassName() string {
 	v, _ := web.MsiValued(i.XItem, []string{"item_subclass", "name"}, "")
-	return v.(string)
+	name, _ := v.(string)
+	return name
 }
 
 // Cosmetic returns true if this item is a cosmetic
@@ -125,34 +194,32 @@ func (i *Item) Cosmetic() bool {
 	return false
 }
 
-// ItemClassName returns the item class name
+// ItemClassName returns the item class name, or "" if it is missing (see Validate)
 func (i *Item) ItemClassName() string {
-	v, err := web.MsiValue(i.XItem, []string{"item_class", "name"})
-	if err != nil {
-		panic(fmt.Errorf("item_class missing from %v: %w", i.XItem, err))
-	}
-	return v.(string)
+	v, _ := web.MsiValued(i.XItem, []string{"item_class", "name"}, "")
+	name, _ := v.(string)
+	return name
 }
 
-// Stackable returns true if the item can be stacked in the inventory
+// Stackable returns true if the item can be stacked in the inventory, false if it is not known (see Validate)
 func (i *Item) Stackable() bool {
-	v, err := web.MsiValue(i.XItem, []string{"is_stackable"})
-	if err != nil {
-		panic(fmt.Errorf("is_stackable missing from %v: %w", i.XItem, err))
-	}
-	return v.(bool)
+	v, _ := web.MsiValued(i.XItem, []string{"is_stackable"}, false)
+	stackable, _ := v.(bool)
+	return stackable
 }
 
-// RelicType returns the relic type
+// RelicType returns the relic type, or "" if it is missing (see Validate)
 func (i *Item) RelicType() string {
 	// The key is only sometimes there; do not error if it is missing
 	v, _ := web.MsiValued(i.XItem, []string{"preview_item", "gem_properties", "relic_type"}, "")
-	return v.(string)
+	relicType, _ := v.(string)
+	return relicType
 }
 
-// Name returns the item name
+// Name returns the item name, or "" if it is missing (see Validate)
 func (i *Item) Name() string {
-	return i.XItem["name"].(string)
+	name, _ := i.XItem["name"].(string)
+	return name
 }
 
 func (i *Item) previewPrice() (int64, error) {
@@ -160,7 +227,7 @@ func (i *Item) previewPrice() (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	return common.JSONInt64Panic(v), nil
+	return common.JSONInt64(v)
 }
 
 // SellPriceAdvertised returns the vendor sell price listed in the JSON
@@ -176,7 +243,7 @@ func (i *Item) SellPriceAdvertised() int64 {
 // SellPriceRealizable returns the actual price the vendor will offer for this specific item
 func (i *Item) SellPriceRealizable() int64 {
 	if i.VariableItemLevel() {
-		// I don't know how to price these
+		// I don't know how to price these; an auction may (see SellPriceListed)
 		return 0
 	}
 	return i.SellPriceAdvertised()
@@ -197,6 +264,29 @@ func (i *Item) Quality() string {
 	return common.JSONString(v)
 }
 
+// Provenance values say where an item's data came from
+const (
+	ProvenanceAPI       = "api"
+	ProvenanceSynthetic = "synthetic"
+)
+
+// Provenance returns where the item data came from. Items without a marker came from the web API.
+func (i *Item) Provenance() string {
+	v, _ := web.MsiValued(i.XItem, []string{"provenance", "type"}, ProvenanceAPI)
+	return common.JSONString(v)
+}
+
+// Source returns what supplied the item data (e.g. the synthetic items data file), if known
+func (i *Item) Source() string {
+	v, _ := web.MsiValued(i.XItem, []string{"provenance", "source"}, "")
+	return common.JSONString(v)
+}
+
+// Synthetic returns true if the item data was made up rather than fetched from the web API
+func (i *Item) Synthetic() bool {
+	return i.Provenance() == ProvenanceSynthetic
+}
+
 // Stale returns whether the item is older than a given number of days
 func (i *Item) Stale(age time.Duration) bool {
 	return time.Since(i.Updated()) > age
@@ -208,6 +298,28 @@ func (i *Item) Toy() bool {
 	return common.JSONString(v) == "Toy"
 }
 
+// Mount returns true if this item teaches a mount
+func (i *Item) Mount() bool {
+	return i.ItemSubclassName() == "Mount"
+}
+
+// Spells returns the names of the spells this item casts or teaches (e.g. the mount it teaches)
+func (i *Item) Spells() []string {
+	names := []string{}
+
+	// Most items have no spells
+	v, _ := web.MsiValued(i.XItem, []string{"preview_item", "spells"}, nil)
+	spells, _ := v.([]any)
+	for _, spell := range spells {
+		name, _ := web.MsiValued(spell, []string{"spell", "name"}, "")
+		if name, ok := name.(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
 // Appearances returns the appearance IDs this item provides
 func (i *Item) Appearances() []int64 {
 	appearanceIDs := []int64{}
@@ -218,10 +330,15 @@ func (i *Item) Appearances() []int64 {
 		return nil
 	}
 
-	appearances := v.([]any)
+	appearances, _ := v.([]any)
 	for _, appearance := range appearances {
-		id := appearance.(map[string]any)["id"]
-		appearanceIDs = append(appearanceIDs, common.JSONInt64Panic(id))
+		id, _ := web.MsiValued(appearance, []string{"id"}, nil)
+		appearanceID, err := common.JSONInt64(id)
+		if err != nil {
+			// Skip it rather than report an appearance that does not exist
+			continue
+		}
+		appearanceIDs = append(appearanceIDs, appearanceID)
 	}
 
 	return appearanceIDs
@@ -2,12 +2,17 @@ package wowitem
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
 
 func testItem(data map[string]any) *Item {
-	return NewItem(data)
+	i, err := NewItem(data)
+	if err != nil {
+		panic(err)
+	}
+	return i
 }
 
 func baseItem() map[string]any {
@@ -41,6 +46,64 @@ func TestNewItem(t *testing.T) {
 	}
 }
 
+func TestNewItemInvalidID(t *testing.T) {
+	for _, id := range []any{nil, "123", json.Number("1.5")} {
+		data := baseItem()
+		data["id"] = id
+		if _, err := NewItem(data); err == nil {
+			t.Errorf("id %#v: expected error", id)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := testItem(baseItem()).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := baseItem()
+	delete(data, "level")
+	data["item_class"] = map[string]any{}
+	data["is_stackable"] = "no"
+	i := testItem(data)
+	err := i.Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, field := range []string{"item 123", "level", "item_class", "is_stackable"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("error %q does not name %s", err, field)
+		}
+	}
+
+	// The accessors report zero values instead of panicking
+	if i.ItemLevel() != 0 || i.ItemClassName() != "" || i.Stackable() {
+		t.Errorf("level %d class %q stackable %t", i.ItemLevel(), i.ItemClassName(), i.Stackable())
+	}
+}
+
+func TestValidateOptionalStrings(t *testing.T) {
+	data := baseItem()
+	data["item_subclass"] = map[string]any{"name": json.Number("4")}
+	data["preview_item"].(map[string]any)["binding"] = map[string]any{"type": map[string]any{}}
+	data["preview_item"].(map[string]any)["gem_properties"] = map[string]any{"relic_type": false}
+	i := testItem(data)
+	err := i.Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, field := range []string{"item_subclass.name", "preview_item.binding.type", "preview_item.gem_properties.relic_type"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("error %q does not name %s", err, field)
+		}
+	}
+
+	// The accessors report zero values instead of panicking
+	if i.ItemSubclassName() != "" || i.Binding() != "" || i.RelicType() != "" || i.Cosmetic() {
+		t.Errorf("subclass %q binding %q relic %q", i.ItemSubclassName(), i.Binding(), i.RelicType())
+	}
+}
+
 func TestItemAccessors(t *testing.T) {
 	i := testItem(baseItem())
 	checks := map[string]string{
@@ -76,6 +139,9 @@ func TestItemAccessors(t *testing.T) {
 	if i.Toy() {
 		t.Errorf("Item is toy, expected it to not be toy")
 	}
+	if i.Mount() {
+		t.Errorf("Item is mount, expected it to not be mount")
+	}
 }
 
 func TestEquippableFallback(t *testing.T) {
@@ -95,6 +161,14 @@ func TestEquippableFallback(t *testing.T) {
 	}
 }
 
+func TestInventoryTypeObject(t *testing.T) {
+	data := baseItem()
+	data["inventory_type"] = map[string]any{"type": "PROFESSION_TOOL", "name": "Profession Tool"}
+	if got := testItem(data).InventoryType(); got != "PROFESSION_TOOL" {
+		t.Errorf("InventoryType=%q", got)
+	}
+}
+
 func TestEquippableAuthoritativeField(t *testing.T) {
 	data := baseItem()
 	data["is_equippable"] = false
@@ -109,11 +183,22 @@ func TestMissingOptionalFields(t *testing.T) {
 	delete(data, "preview_item")
 	delete(data, "inventory_type")
 	i := testItem(data)
-	if i.Binding() != "" || i.InventoryType() != "UNKNOWN" || i.RelicType() != "" || i.Quality() != "" || i.Requirements() != "" || i.SellPriceAdvertised() != 0 || i.Toy() {
+	if i.Binding() != "" || i.InventoryType() != "UNKNOWN" || i.RelicType() != "" || i.Quality() != "" || i.Requirements() != "" || i.SellPriceAdvertised() != 0 || i.Toy() || len(i.Spells()) != 0 {
 		t.Error("missing optional fields not handled")
 	}
 }
 
+func TestSpells(t *testing.T) {
+	data := baseItem()
+	data["preview_item"].(map[string]any)["spells"] = []any{
+		map[string]any{"spell": map[string]any{"name": "Azure Drake"}},
+		map[string]any{"spell": map[string]any{}},
+	}
+	if got := testItem(data).Spells(); len(got) != 1 || got[0] != "Azure Drake" {
+		t.Errorf("Spells() = %v", got)
+	}
+}
+
 func TestCosmetic(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -163,6 +248,60 @@ func TestSellPriceRealizableVariableLevel(t *testing.T) {
 	}
 }
 
+func TestSellPriceAt(t *testing.T) {
+	armor := testItem(baseItem())
+
+	stackable := baseItem()
+	stackable["is_stackable"] = true
+
+	free := baseItem()
+	delete(free["preview_item"].(map[string]any), "sell_price")
+
+	tests := []struct {
+		name       string
+		item       *Item
+		iLevel     int64
+		value      int64
+		confidence Confidence
+	}{
+		{"base iLevel", armor, 100, 123456, ConfidenceValidated},
+		{"upgraded", armor, 150, 185184, ConfidenceEstimated},
+		{"unknown iLevel", armor, 0, 0, ConfidenceNone},
+		{"fixed iLevel", testItem(stackable), 0, 123456, ConfidenceValidated},
+		{"no sell price", testItem(free), 100, 0, ConfidenceNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.item.SellPriceAt(tt.iLevel)
+			if got.Value != tt.value || got.Confidence != tt.confidence {
+				t.Errorf("SellPriceAt(%d) = %+v, want %d %s", tt.iLevel, got, tt.value, tt.confidence)
+			}
+			want := int64(0)
+			if tt.confidence == ConfidenceValidated {
+				want = tt.value
+			}
+			if got.Realizable() != want {
+				t.Errorf("Realizable() = %d, want %d", got.Realizable(), want)
+			}
+		})
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	i := testItem(baseItem())
+	if i.Provenance() != ProvenanceAPI || i.Synthetic() || i.Source() != "" {
+		t.Errorf("unmarked item: provenance %q synthetic %t source %q", i.Provenance(), i.Synthetic(), i.Source())
+	}
+
+	data := baseItem()
+	data["provenance"] = map[string]any{"type": "synthetic", "source": "synthetics.json"}
+	i = testItem(data)
+	if i.Provenance() != ProvenanceSynthetic || !i.Synthetic() || i.Source() != "synthetics.json" {
+		t.Errorf("synthetic item: provenance %q synthetic %t source %q", i.Provenance(), i.Synthetic(), i.Source())
+	}
+}
+
 func TestStale(t *testing.T) {
 	i := testItem(baseItem())
 	i.XUpdated = time.Now().Add(-48 * time.Hour)
@@ -173,3 +312,34 @@ func TestStale(t *testing.T) {
 		t.Error("recent-enough item should not be stale")
 	}
 }
+
+func TestSellPriceListed(t *testing.T) {
+	armor := testItem(baseItem())
+
+	stackable := baseItem()
+	stackable["is_stackable"] = true
+
+	tests := []struct {
+		name       string
+		item       *Item
+		bonusList  []int64
+		iLevel     int64
+		value      int64
+		confidence Confidence
+	}{
+		{"no bonuses", armor, nil, 100, 123456, ConfidenceValidated},
+		{"bonuses at base iLevel", armor, []int64{6652, 1472}, 100, 123456, ConfidenceEstimated},
+		{"upgraded", armor, []int64{6652, 1482}, 110, 135801, ConfidenceEstimated},
+		{"unknown bonuses", armor, []int64{12240}, 0, 0, ConfidenceNone},
+		{"fixed iLevel", testItem(stackable), []int64{6652}, 0, 123456, ConfidenceValidated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.item.SellPriceListed(tt.bonusList, tt.iLevel)
+			if got.Value != tt.value || got.Confidence != tt.confidence {
+				t.Errorf("SellPriceListed(%v, %d) = %+v, want %d %s", tt.bonusList, tt.iLevel, got, tt.value, tt.confidence)
+			}
+		})
+	}
+}
@@ -45,7 +45,12 @@ func GetToken(data url.Values, clientID, clientSecret string) (string, error) {
 		return "", fmt.Errorf("unexpected status code: %d", response.StatusCode)
 	}
 
-	return jsonObject["access_token"].(string), nil
+	token, ok := jsonObject["access_token"].(string)
+	if !ok {
+		return "", fmt.Errorf("access_token has type %T, want string", jsonObject["access_token"])
+	}
+
+	return token, nil
 }
 
 // GetPAT returns a profile access token (to authenticate user profile API calls)